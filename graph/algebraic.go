@@ -0,0 +1,417 @@
+package graph
+
+import (
+	"github.com/Tom-Johnston/mamba/comb"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//finiteField is the finite field GF(p^e). The element x represents the polynomial whose coefficients are the base p digits of x (with the constant term first) and the arithmetic is modulo a monic irreducible polynomial of degree e.
+type finiteField struct {
+	p, e, q int
+	modulus []int //The coefficients of the irreducible polynomial with the constant term first.
+
+	//For small fields, the results of sub and mul are stored in tables.
+	subTable [][]int
+	mulTable [][]int
+}
+
+//maxTableSize is the largest field for which the tables are used.
+const maxTableSize = 256
+
+//primePower returns p and e such that q = p^e for a prime p or ok = false if q isn't a prime power.
+func primePower(q int) (p, e int, ok bool) {
+	if q < 2 {
+		return 0, 0, false
+	}
+	p = 2
+	for p*p <= q && q%p != 0 {
+		p++
+	}
+	if q%p != 0 {
+		p = q
+	}
+	for q%p == 0 {
+		q /= p
+		e++
+	}
+	return p, e, q == 1
+}
+
+//newFiniteField returns the finite field with q elements. This panics if q isn't a prime power.
+func newFiniteField(q int) *finiteField {
+	p, e, ok := primePower(q)
+	if !ok {
+		panic("q must be a prime power.")
+	}
+	f := &finiteField{p: p, e: e, q: q}
+	//Try the monic polynomials of degree e in turn until one is irreducible.
+	for c := 0; ; c++ {
+		modulus := append(f.digits(c, e), 1)
+		if isIrreducible(modulus, p) {
+			f.modulus = modulus
+			break
+		}
+	}
+	if q <= maxTableSize {
+		subTable := make([][]int, q)
+		mulTable := make([][]int, q)
+		for x := 0; x < q; x++ {
+			subTable[x] = make([]int, q)
+			mulTable[x] = make([]int, q)
+			for y := 0; y < q; y++ {
+				subTable[x][y] = f.sub(x, y)
+				mulTable[x][y] = f.mul(x, y)
+			}
+		}
+		f.subTable = subTable
+		f.mulTable = mulTable
+	}
+	return f
+}
+
+//isIrreducible returns true if the monic polynomial a over GF(p) has no monic factor of degree between 1 and deg(a)/2.
+func isIrreducible(a []int, p int) bool {
+	n := len(a) - 1
+	for d := 1; 2*d <= n; d++ {
+		count := 1
+		for i := 0; i < d; i++ {
+			count *= p
+		}
+		for c := 0; c < count; c++ {
+			divisor := make([]int, d+1)
+			x := c
+			for i := 0; i < d; i++ {
+				divisor[i] = x % p
+				x /= p
+			}
+			divisor[d] = 1
+			//Reduce a modulo the monic divisor and check if the remainder is 0.
+			r := append([]int(nil), a...)
+			for i := n; i >= d; i-- {
+				coeff := r[i]
+				for j := 0; j <= d; j++ {
+					r[i-d+j] = ((r[i-d+j]-coeff*divisor[j])%p + p) % p
+				}
+			}
+			zero := true
+			for _, v := range r[:d] {
+				if v != 0 {
+					zero = false
+				}
+			}
+			if zero {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+//digits returns the first k base p digits of x.
+func (f *finiteField) digits(x, k int) []int {
+	d := make([]int, k)
+	for i := range d {
+		d[i] = x % f.p
+		x /= f.p
+	}
+	return d
+}
+
+//fromDigits returns the element with the given base p digits.
+func (f *finiteField) fromDigits(d []int) int {
+	x := 0
+	for i := len(d) - 1; i >= 0; i-- {
+		x = x*f.p + d[i]
+	}
+	return x
+}
+
+//sub returns x - y.
+func (f *finiteField) sub(x, y int) int {
+	if f.subTable != nil {
+		return f.subTable[x][y]
+	}
+	a, b := f.digits(x, f.e), f.digits(y, f.e)
+	for i := range a {
+		a[i] = (a[i] - b[i] + f.p) % f.p
+	}
+	return f.fromDigits(a)
+}
+
+//mul returns xy.
+func (f *finiteField) mul(x, y int) int {
+	if f.mulTable != nil {
+		return f.mulTable[x][y]
+	}
+	a, b := f.digits(x, f.e), f.digits(y, f.e)
+	prod := make([]int, 2*f.e-1)
+	for i, u := range a {
+		for j, v := range b {
+			prod[i+j] = (prod[i+j] + u*v) % f.p
+		}
+	}
+	//Reduce modulo the monic modulus.
+	for i := len(prod) - 1; i >= f.e; i-- {
+		coeff := prod[i]
+		for j := 0; j <= f.e; j++ {
+			prod[i-f.e+j] = ((prod[i-f.e+j]-coeff*f.modulus[j])%f.p + f.p) % f.p
+		}
+	}
+	return f.fromDigits(prod[:f.e])
+}
+
+//inv returns the multiplicative inverse of x != 0 which is x^(q - 2).
+func (f *finiteField) inv(x int) int {
+	result := 1
+	for k := f.q - 2; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			result = f.mul(result, x)
+		}
+		x = f.mul(x, x)
+	}
+	return result
+}
+
+//rank returns the rank of the matrix over the field. The matrix is modified.
+func (f *finiteField) rank(matrix [][]int) int {
+	rank := 0
+	if len(matrix) == 0 {
+		return 0
+	}
+	for col := 0; col < len(matrix[0]) && rank < len(matrix); col++ {
+		pivot := -1
+		for i := rank; i < len(matrix); i++ {
+			if matrix[i][col] != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot == -1 {
+			continue
+		}
+		matrix[rank], matrix[pivot] = matrix[pivot], matrix[rank]
+		scale := f.inv(matrix[rank][col])
+		for j := range matrix[rank] {
+			matrix[rank][j] = f.mul(matrix[rank][j], scale)
+		}
+		for i := rank + 1; i < len(matrix); i++ {
+			if c := matrix[i][col]; c != 0 {
+				for j := range matrix[i] {
+					matrix[i][j] = f.sub(matrix[i][j], f.mul(c, matrix[rank][j]))
+				}
+			}
+		}
+		rank++
+	}
+	return rank
+}
+
+//PaleyGraph returns the Paley graph of order q. The vertices are the elements of the finite field GF(q) and x and y are adjacent if x - y is a non-zero square.
+//The element x of GF(p^e) is the polynomial whose coefficients are the base p digits of x so, when q is prime, the vertex x is the integer x mod q. This panics if q isn't a prime power congruent to 1 mod 4.
+func PaleyGraph(q int) *DenseGraph {
+	if q%4 != 1 {
+		panic("q must be a prime power congruent to 1 mod 4.")
+	}
+	f := newFiniteField(q)
+	squares := make([]bool, q)
+	for x := 1; x < q; x++ {
+		squares[f.mul(x, x)] = true
+	}
+	g := NewDense(q, nil)
+	for x := 0; x < q; x++ {
+		for y := 0; y < x; y++ {
+			if squares[f.sub(x, y)] {
+				g.AddEdge(x, y)
+			}
+		}
+	}
+	return g
+}
+
+//JohnsonGraph returns the Johnson graph J(n, k). It has a vertex for each unordered subset of {0,...,n-1} of size k and an edge between two subsets if their intersection has size k - 1.
+//The subsets are ordered in co-lexicographic order as in KneserGraph.
+func JohnsonGraph(n, k int) *DenseGraph {
+	N := comb.Coeff(n, k)
+	g := NewDense(N, nil)
+	for i := 0; i < N; i++ {
+		combi := comb.Unrank(i, k)
+		for j := 0; j < i; j++ {
+			combj := comb.Unrank(j, k)
+			if sortints.IntersectionSize(combi, combj) == k-1 {
+				g.AddEdge(i, j)
+			}
+		}
+	}
+	return g
+}
+
+//HammingGraph returns the Hamming graph H(d, q). It has a vertex for each word of length d over the alphabet {0,...,q-1} and an edge between two words if they differ in exactly one position.
+//The word with letters a_0, ..., a_{d-1} is the vertex a_0 + a_1 q + ... + a_{d-1} q^(d-1) so HammingGraph(d, 2) is HypercubeGraph(d).
+func HammingGraph(d, q int) *DenseGraph {
+	if d < 0 || q < 1 {
+		panic("d must be non-negative and q must be positive")
+	}
+	n := 1
+	for i := 0; i < d; i++ {
+		n *= q
+	}
+	g := NewDense(n, nil)
+	for v := 0; v < n; v++ {
+		power := 1
+		for i := 0; i < d; i++ {
+			letter := (v / power) % q
+			//Change the letter in position i to each larger letter.
+			for b := letter + 1; b < q; b++ {
+				g.AddEdge(v, v+(b-letter)*power)
+			}
+			power *= q
+		}
+	}
+	return g
+}
+
+//GrassmannGraph returns the Grassmann graph J_q(n, k). It has a vertex for each k-dimensional subspace of the vector space GF(q)^n and an edge between two subspaces if their intersection has dimension k - 1.
+//The subspaces are listed by their reduced row echelon forms in increasing order of the pivot columns (in co-lexicographic order) and then of the entries. This panics if q isn't a prime power.
+func GrassmannGraph(q, n, k int) *DenseGraph {
+	f := newFiniteField(q)
+	if k < 0 || k > n {
+		panic("k must be between 0 and n")
+	}
+	subspaces := make([][][]int, 0)
+	pivots := comb.Coeff(n, k)
+	for r := 0; r < pivots; r++ {
+		pivotColumns := comb.Unrank(r, k)
+		//The free entries are the entries to the right of the pivot in each row which aren't in a pivot column.
+		free := make([][2]int, 0)
+		for i, c := range pivotColumns {
+			for j := c + 1; j < n; j++ {
+				if !sortints.ContainsSingle(pivotColumns, j) {
+					free = append(free, [2]int{i, j})
+				}
+			}
+		}
+		count := 1
+		for range free {
+			count *= q
+		}
+		for x := 0; x < count; x++ {
+			matrix := make([][]int, k)
+			for i, c := range pivotColumns {
+				matrix[i] = make([]int, n)
+				matrix[i][c] = 1
+			}
+			y := x
+			for _, pos := range free {
+				matrix[pos[0]][pos[1]] = y % q
+				y /= q
+			}
+			subspaces = append(subspaces, matrix)
+		}
+	}
+
+	g := NewDense(len(subspaces), nil)
+	stacked := make([][]int, 2*k)
+	for i := range stacked {
+		stacked[i] = make([]int, n)
+	}
+	for a := range subspaces {
+		for b := 0; b < a; b++ {
+			for i := 0; i < k; i++ {
+				copy(stacked[i], subspaces[a][i])
+				copy(stacked[k+i], subspaces[b][i])
+			}
+			//The intersection has dimension 2k - rank.
+			if f.rank(stacked) == k+1 {
+				g.AddEdge(a, b)
+			}
+		}
+	}
+	return g
+}
+
+//CayleyGraph returns the Cayley graph of the group with multiplication table table and connection set connection. The group elements are 0, ..., n-1 and the product of a and b is table[a][b].
+//There is an edge between g and gs for each g in the group and s in connection so the graph is the Cayley graph with connection set connection ∪ connection^(-1). This panics if the table isn't square or the connection set contains the identity.
+func CayleyGraph(table [][]int, connection []int) *DenseGraph {
+	n := len(table)
+	for _, row := range table {
+		if len(row) != n {
+			panic("The multiplication table must be square.")
+		}
+	}
+	for _, s := range connection {
+		for a := 0; a < n; a++ {
+			if table[a][s] == a {
+				panic("The connection set can't contain the identity.")
+			}
+		}
+	}
+	g := NewDense(n, nil)
+	for a := 0; a < n; a++ {
+		for _, s := range connection {
+			g.AddEdge(a, table[a][s])
+		}
+	}
+	return g
+}
+
+//CayleyGraphPermutations returns the Cayley graph of the permutation group generated by generators with connection set connection. The permutations act on {0, ..., d-1} and the product gh is the permutation which applies g and then h i.e. (gh)[x] = h[g[x]].
+//The group elements are found by a breadth first search from the identity, which is the vertex 0, multiplying by the generators in order on the right. There is an edge between g and gs for each g in the group and s in connection so the graph is the Cayley graph with connection set connection ∪ connection^(-1).
+//This panics if the permutations don't have the same length, if an element of connection isn't in the group or if connection contains the identity.
+func CayleyGraphPermutations(generators [][]int, connection [][]int) *DenseGraph {
+	d := 0
+	if len(generators) > 0 {
+		d = len(generators[0])
+	}
+	for _, p := range append(append([][]int{}, generators...), connection...) {
+		if len(p) != d {
+			panic("The permutations must have the same length.")
+		}
+	}
+	key := func(p []int) string {
+		b := make([]byte, 0, 4*len(p))
+		for _, x := range p {
+			b = append(b, byte(x), byte(x>>8), byte(x>>16), byte(x>>24))
+		}
+		return string(b)
+	}
+	product := func(g, h []int) []int {
+		gh := make([]int, len(g))
+		for x := range g {
+			gh[x] = h[g[x]]
+		}
+		return gh
+	}
+
+	identity := make([]int, d)
+	for i := range identity {
+		identity[i] = i
+	}
+	elements := [][]int{identity}
+	index := map[string]int{key(identity): 0}
+	for i := 0; i < len(elements); i++ {
+		for _, s := range generators {
+			h := product(elements[i], s)
+			if _, ok := index[key(h)]; !ok {
+				index[key(h)] = len(elements)
+				elements = append(elements, h)
+			}
+		}
+	}
+
+	for _, s := range connection {
+		j, ok := index[key(s)]
+		if !ok {
+			panic("The connection set must be contained in the group.")
+		}
+		if j == 0 {
+			panic("The connection set can't contain the identity.")
+		}
+	}
+	g := NewDense(len(elements), nil)
+	for i, a := range elements {
+		for _, s := range connection {
+			g.AddEdge(i, index[key(product(a, s))])
+		}
+	}
+	return g
+}
@@ -0,0 +1,197 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//srgParameters returns the parameters (n, k, lambda, mu) of g and true if g is strongly regular and false otherwise.
+func srgParameters(g graph.Graph) ([4]int, bool) {
+	n := g.N()
+	params := [4]int{n, -1, -1, -1}
+	for v := 0; v < n; v++ {
+		if params[1] == -1 {
+			params[1] = len(g.Neighbours(v))
+		} else if len(g.Neighbours(v)) != params[1] {
+			return params, false
+		}
+		for u := 0; u < v; u++ {
+			common := 0
+			for w := 0; w < n; w++ {
+				if g.IsEdge(u, w) && g.IsEdge(v, w) {
+					common++
+				}
+			}
+			i := 3
+			if g.IsEdge(u, v) {
+				i = 2
+			}
+			if params[i] == -1 {
+				params[i] = common
+			} else if params[i] != common {
+				return params, false
+			}
+		}
+	}
+	return params, true
+}
+
+func TestPaleyGraph(t *testing.T) {
+	for _, q := range []int{5, 9, 13, 17, 25, 29, 49, 81, 121, 125} {
+		g := graph.PaleyGraph(q)
+		params, ok := srgParameters(g)
+		expected := [4]int{q, (q - 1) / 2, (q - 5) / 4, (q - 1) / 4}
+		if !ok || params != expected {
+			t.Errorf("The Paley graph of order %v is not strongly regular with parameters %v", q, expected)
+		}
+	}
+	//The Paley graph of order 9 is the 3 x 3 rook graph.
+	if !isomorphic(graph.PaleyGraph(9), graph.RookGraph(3, 3)) {
+		t.Errorf("The Paley graph of order 9 is not the rook graph")
+	}
+	for _, q := range []int{3, 15, 21, 45} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected a panic for q = %v", q)
+				}
+			}()
+			graph.PaleyGraph(q)
+		}()
+	}
+}
+
+func TestJohnsonGraph(t *testing.T) {
+	//J(n, 2) is the complement of the Kneser graph K(n, 2) and is strongly regular.
+	for n := 4; n < 9; n++ {
+		g := graph.JohnsonGraph(n, 2)
+		if graph.Graph6Encode(g) != graph.Graph6Encode(graph.ComplementDense(graph.KneserGraph(n, 2))) {
+			t.Errorf("J(%v, 2) is not the complement of K(%v, 2)", n, n)
+		}
+		params, ok := srgParameters(g)
+		expected := [4]int{n * (n - 1) / 2, 2 * (n - 2), n - 2, 4}
+		if !ok || params != expected {
+			t.Errorf("J(%v, 2) is not strongly regular with parameters %v", n, expected)
+		}
+	}
+	//J(n, k) is k(n - k)-regular with diameter min(k, n - k).
+	for _, test := range [][2]int{{6, 3}, {7, 3}, {8, 4}, {9, 2}} {
+		n, k := test[0], test[1]
+		g := graph.JohnsonGraph(n, k)
+		if graph.MinDegree(g) != k*(n-k) || graph.MaxDegree(g) != k*(n-k) {
+			t.Errorf("J(%v, %v) is not %v-regular", n, k, k*(n-k))
+		}
+		diameter := k
+		if n-k < k {
+			diameter = n - k
+		}
+		if graph.Diameter(g) != diameter {
+			t.Errorf("Wrong diameter for J(%v, %v). Found: %v Expected: %v", n, k, graph.Diameter(g), diameter)
+		}
+	}
+}
+
+func TestHammingGraph(t *testing.T) {
+	for d := 0; d < 6; d++ {
+		if graph.Graph6Encode(graph.HammingGraph(d, 2)) != graph.Graph6Encode(graph.HypercubeGraph(d)) {
+			t.Errorf("H(%v, 2) is not the hypercube", d)
+		}
+	}
+	if !isomorphic(graph.HammingGraph(2, 4), graph.RookGraph(4, 4)) {
+		t.Errorf("H(2, 4) is not the rook graph")
+	}
+	g := graph.HammingGraph(3, 4)
+	if g.N() != 64 || graph.MinDegree(g) != 9 || graph.MaxDegree(g) != 9 || graph.Diameter(g) != 3 {
+		t.Errorf("H(3, 4) has the wrong parameters")
+	}
+}
+
+func TestGrassmannGraph(t *testing.T) {
+	tests := []struct {
+		q, n, k int
+		params  [4]int
+	}{
+		//J_q(n, 2) is strongly regular with parameters given by the Gaussian binomials.
+		{2, 4, 2, [4]int{35, 18, 9, 9}},
+		{3, 4, 2, [4]int{130, 48, 20, 16}},
+		{2, 5, 2, [4]int{155, 42, 17, 9}},
+		{4, 4, 2, [4]int{357, 100, 35, 25}},
+	}
+	for _, test := range tests {
+		g := graph.GrassmannGraph(test.q, test.n, test.k)
+		params, ok := srgParameters(g)
+		if !ok || params != test.params {
+			t.Errorf("J_%v(%v, %v) is not strongly regular with parameters %v. Found: %v", test.q, test.n, test.k, test.params, params)
+		}
+	}
+	//J_q(n, 1) is complete.
+	if g := graph.GrassmannGraph(3, 3, 1); graph.Graph6Encode(g) != graph.Graph6Encode(graph.CompleteGraph(13)) {
+		t.Errorf("J_3(3, 1) is not K_13")
+	}
+	//J_2(6, 3) has 1395 vertices, degree 98 and diameter 3.
+	g := graph.GrassmannGraph(2, 6, 3)
+	//Copy the graph to a sparse graph as Diameter calls Neighbours many times.
+	neighbourhoods := make([]sortints.SortedInts, g.N())
+	for v := range neighbourhoods {
+		neighbourhoods[v] = g.Neighbours(v)
+	}
+	h := graph.NewSparse(g.N(), neighbourhoods)
+	if h.N() != 1395 || graph.MinDegree(h) != 98 || graph.MaxDegree(h) != 98 || graph.Diameter(h) != 3 {
+		t.Errorf("J_2(6, 3) has the wrong parameters")
+	}
+}
+
+func TestCayleyGraph(t *testing.T) {
+	//The Cayley graph of Z_n is a circulant graph.
+	n := 10
+	table := make([][]int, n)
+	for a := range table {
+		table[a] = make([]int, n)
+		for b := range table[a] {
+			table[a][b] = (a + b) % n
+		}
+	}
+	if graph.Graph6Encode(graph.CayleyGraph(table, []int{1, 3})) != graph.Graph6Encode(graph.CirculantGraph(n, 1, 3)) {
+		t.Errorf("The Cayley graph of Z_10 is not the circulant graph")
+	}
+
+	//The Shrikhande graph is a Cayley graph of Z_4 x Z_4.
+	table = make([][]int, 16)
+	for a := range table {
+		table[a] = make([]int, 16)
+		for b := range table[a] {
+			table[a][b] = 4*((a/4+b/4)%4) + (a+b)%4
+		}
+	}
+	shrikhande, _ := graph.NamedGraph("Shrikhande")
+	if !isomorphic(graph.CayleyGraph(table, []int{4, 12, 1, 3, 5, 15}), shrikhande) {
+		t.Errorf("The Cayley graph of Z_4 x Z_4 is not the Shrikhande graph")
+	}
+
+	//The Cayley graph of S_4 with the adjacent transpositions is the permutohedron.
+	transpositions := [][]int{{1, 0, 2, 3}, {0, 2, 1, 3}, {0, 1, 3, 2}}
+	g := graph.CayleyGraphPermutations(transpositions, transpositions)
+	if g.N() != 24 || g.M() != 36 || graph.Girth(g) != 4 || graph.Diameter(g) != 6 || !graph.IsPlanar(g) {
+		t.Errorf("The Cayley graph of S_4 is not the permutohedron")
+	}
+	//Every transposition gives the complete bipartite graph K_{3, 3} for S_3.
+	g = graph.CayleyGraphPermutations([][]int{{1, 0, 2}, {1, 2, 0}}, [][]int{{1, 0, 2}, {0, 2, 1}, {2, 1, 0}})
+	if !isomorphic(g, graph.CompletePartiteGraph(3, 3)) {
+		t.Errorf("The Cayley graph of S_3 with the transpositions is not K_{3, 3}")
+	}
+	//A 5-cycle and its inverse in the cyclic group give C_5.
+	g = graph.CayleyGraphPermutations([][]int{{1, 2, 3, 4, 0}}, [][]int{{1, 2, 3, 4, 0}})
+	if !isomorphic(g, graph.Cycle(5)) {
+		t.Errorf("The Cayley graph of Z_5 is not C_5")
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic for a connection set outside the group")
+			}
+		}()
+		graph.CayleyGraphPermutations([][]int{{1, 2, 0}}, [][]int{{1, 0, 2}})
+	}()
+}
@@ -48,7 +48,7 @@ func NewOrderedPartition(n, m int, vertexClasses [][]int) *CanonicalOrderedParti
 			for j := range vertexClasses[i] {
 				v := vertexClasses[i][j]
 				order[index] = v
-				inCell[v] = j
+				inCell[v] = i
 				index++
 			}
 			binDividers[i] = index
@@ -59,8 +59,11 @@ func NewOrderedPartition(n, m int, vertexClasses [][]int) *CanonicalOrderedParti
 	for i := range binAges {
 		binAges[i] = 0
 	}
-	binsToCheck := make([]int, 1, n)
-	binsToCheck[0] = 0
+	//Every bin could shatter another bin.
+	binsToCheck := make([]int, len(binDividers), n)
+	for i := range binsToCheck {
+		binsToCheck[i] = i
+	}
 	value := make([]int, 0, m)
 	return &CanonicalOrderedPartition{order: order, binDividers: binDividers, binAges: binAges, binsToCheck: binsToCheck, value: value, inCell: inCell}
 }
@@ -98,7 +101,7 @@ func (op *CanonicalOrderedPartition) Reset(n, m int, vertexClasses [][]int) {
 			for j := range vertexClasses[i] {
 				v := vertexClasses[i][j]
 				op.order[index] = v
-				op.inCell[v] = j
+				op.inCell[v] = i
 				index++
 			}
 			op.binDividers[i] = index
@@ -110,9 +113,10 @@ func (op *CanonicalOrderedPartition) Reset(n, m int, vertexClasses [][]int) {
 		op.binAges[i] = 0
 	}
 
-	if n > 0 {
-		op.binsToCheck = op.binsToCheck[:1]
-		op.binsToCheck[0] = 0
+	//Every bin could shatter another bin.
+	op.binsToCheck = op.binsToCheck[:len(op.binDividers)]
+	for i := range op.binsToCheck {
+		op.binsToCheck[i] = i
 	}
 
 	op.value = op.value[:0]
@@ -515,53 +519,38 @@ func CanonicalIsomorphAllocated(n, m int, neighbours [][]int, op *CanonicalOrder
 	//Handle the special case where m = 0.
 	//TODO: Check if this is necessary.
 	if m == 0 {
-		//Return the identity permutation.
+		//The vertices keep the order given by the vertex classes.
 		perm := storage.currentBestPerm[:n]
-		for i := 0; i < n; i++ {
-			perm[i] = i
-		}
-		//Every vertex is in the same orbit.
+		copy(perm, op.order)
+		//Every vertex class is an orbit and each is generated by an n-cycle and a transposition.
 		ds := storage.firstLeafOrbits[:n]
-		ds[0] = -2
-		for i := 1; i < n; i++ {
-			ds[i] = 0
-		}
-
-		if n == 1 {
-			return perm, ds, storage.generators[:0]
-		}
-
-		generators := storage.generators[:1]
-		tmp := generators[0]
-		if cap(tmp) < n {
-			tmp = make([]int, n)
-		} else {
-			tmp = tmp[:n]
-		}
-		for i := range tmp {
-			tmp[i] = i + 1
-		}
-		tmp[n-1] = 0
-		generators[0] = tmp
-
-		if n == 2 {
-			return perm, ds, generators
-		}
-
-		generators = generators[:2]
-
-		tmp = generators[1]
-		if cap(tmp) < n {
-			tmp = make([]int, n)
-		} else {
-			tmp = tmp[:n]
-		}
-		for i := range tmp {
-			tmp[i] = i
+		prevBinStart := 0
+		for _, binEnd := range op.binDividers {
+			binSize := binEnd - prevBinStart
+			root := op.order[prevBinStart]
+			ds[root] = -1
+			if binSize > 1 {
+				ds[root] = -2
+			}
+			for i := prevBinStart + 1; i < binEnd; i++ {
+				ds[op.order[i]] = root
+			}
+			if binSize > 1 {
+				generators = appendGenerator(generators, n)
+				tmp := generators[len(generators)-1]
+				for i := prevBinStart; i < binEnd-1; i++ {
+					tmp[op.order[i]] = op.order[i+1]
+				}
+				tmp[op.order[binEnd-1]] = root
+			}
+			if binSize > 2 {
+				generators = appendGenerator(generators, n)
+				tmp := generators[len(generators)-1]
+				tmp[op.order[prevBinStart]] = op.order[prevBinStart+1]
+				tmp[op.order[prevBinStart+1]] = op.order[prevBinStart]
+			}
+			prevBinStart = binEnd
 		}
-		tmp[0] = 1
-		tmp[1] = 0
-		generators[1] = tmp
 		return perm, ds, generators
 	}
 
@@ -587,6 +576,9 @@ func CanonicalIsomorphAllocated(n, m int, neighbours [][]int, op *CanonicalOrder
 
 	skipDeage := false
 
+	//The initial partition may already start with some singleton bins which won't be split.
+	op.expandValue(neighbours, currentBest, firstLeaf)
+
 	//Split the partition.
 	//We split here and at the end of the loop so we can easily handle the CheckViable option. It wouldn't be hard to check it the other way but might require a
 	worse := equitableRefinementProcedure(neighbours, op, dws, nbs, space, timesSeen, maxCell, numberOfMax, currentBest, firstLeaf, options)
@@ -793,6 +785,26 @@ func CanonicalIsomorphAllocated(n, m int, neighbours [][]int, op *CanonicalOrder
 
 //Below are various helper functions.
 
+//appendGenerator extends generators by one permutation of size n, reusing the existing storage if possible, and sets it to be the identity.
+func appendGenerator(generators [][]int, n int) [][]int {
+	if len(generators) < cap(generators) {
+		generators = generators[:len(generators)+1]
+	} else {
+		generators = append(generators, nil)
+	}
+	tmp := generators[len(generators)-1]
+	if cap(tmp) >= n {
+		tmp = tmp[:n]
+	} else {
+		tmp = make([]int, n)
+	}
+	for i := range tmp {
+		tmp[i] = i
+	}
+	generators[len(generators)-1] = tmp
+	return generators
+}
+
 //zeroOut sets all the entries of a to be 0.
 //Note that this will be optimised to a memclr call.
 func zeroOut(a []int) {
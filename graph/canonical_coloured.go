@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"math/bits"
+	"sort"
+
+	"github.com/Tom-Johnston/mamba/disjoint"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//CanonicalIsomorphDigraph returns the permutation which when applied to the digraph g gives the canonical isomorph, a disjoint.Set giving the vertex orbits and a set of generators for the automorphism group of g.
+//The vertexClasses behave as in CanonicalIsomorphFull and only isomorphisms which map each vertex class to itself are considered. If vertexClasses is nil, all the vertices are in the same class.
+//The canonical isomorph can be obtained by running InducedSubgraph(perm) and two digraphs have the same canonical isomorph if and only if they are isomorphic.
+//The digraph is converted into a graph on 3n vertices. The vertex v becomes the path v, n + v, 2n + v and the arc (u, v) becomes the edge between u and 2n + v. Each of the three layers is given its own vertex classes so isomorphisms of the graph correspond to isomorphisms of the digraph.
+func CanonicalIsomorphDigraph(g Digraph, vertexClasses [][]int) ([]int, disjoint.Set, [][]int) {
+	n := g.N()
+	neighbourhoods := make([]sortints.SortedInts, 3*n)
+	for v := 0; v < n; v++ {
+		neighbourhoods[v] = append(neighbourhoods[v], n+v)
+		neighbourhoods[n+v] = append(neighbourhoods[n+v], v, 2*n+v)
+		neighbourhoods[2*n+v] = append(neighbourhoods[2*n+v], n+v)
+		for _, u := range g.OutNeighbours(v) {
+			neighbourhoods[v] = append(neighbourhoods[v], 2*n+u)
+			neighbourhoods[2*n+u] = append(neighbourhoods[2*n+u], v)
+		}
+	}
+	h := NewSparse(3*n, neighbourhoods)
+	return canonicalIsomorphLayered(h, n, 3, vertexClasses)
+}
+
+//CanonicalIsomorphEdgeColoured returns the permutation which when applied to g gives the canonical isomorph of g as an edge-coloured graph, a disjoint.Set giving the vertex orbits and a set of generators for the automorphism group of g.
+//The colour of the edge ij is given by edgeColour(i, j) and only isomorphisms which preserve the colours of the edges are considered. The function edgeColour must be symmetric and is only called on the edges of g. The actual values of the colours matter and not just the partition of the edges they give e.g. a graph with every edge coloured 1 is not isomorphic to the same graph with every edge coloured 2.
+//The vertexClasses behave as in CanonicalIsomorphFull. If vertexClasses is nil, all the vertices are in the same class.
+//This uses the layering described in the nauty user guide. If there are k colours, they are ranked from 1 to k and the graph is copied into L layers where L is the number of bits needed to write k. The copies of each vertex are joined in a path and the edge ij with colour of rank r is present in layer l if the lth bit of r is 1.
+func CanonicalIsomorphEdgeColoured(g Graph, edgeColour func(i, j int) int, vertexClasses [][]int) ([]int, disjoint.Set, [][]int) {
+	n := g.N()
+	type colouredEdge struct {
+		i, j, colour int
+	}
+	edges := make([]colouredEdge, 0, g.M())
+	colours := make([]int, 0, g.M())
+	for j := 0; j < n; j++ {
+		for _, i := range g.Neighbours(j) {
+			if i >= j {
+				break
+			}
+			c := edgeColour(i, j)
+			edges = append(edges, colouredEdge{i: i, j: j, colour: c})
+			colours = append(colours, c)
+		}
+	}
+	sort.Ints(colours)
+	distinct := colours[:0]
+	for i, c := range colours {
+		if i == 0 || c != colours[i-1] {
+			distinct = append(distinct, c)
+		}
+	}
+
+	layers := bits.Len(uint(len(distinct)))
+	if layers == 0 {
+		layers = 1
+	}
+
+	neighbourhoods := make([]sortints.SortedInts, layers*n)
+	for l := 0; l < layers-1; l++ {
+		for v := 0; v < n; v++ {
+			neighbourhoods[l*n+v] = append(neighbourhoods[l*n+v], (l+1)*n+v)
+			neighbourhoods[(l+1)*n+v] = append(neighbourhoods[(l+1)*n+v], l*n+v)
+		}
+	}
+	for _, e := range edges {
+		rank := uint(sort.SearchInts(distinct, e.colour) + 1)
+		for l := 0; l < layers; l++ {
+			if rank&(1<<uint(l)) != 0 {
+				neighbourhoods[l*n+e.i] = append(neighbourhoods[l*n+e.i], l*n+e.j)
+				neighbourhoods[l*n+e.j] = append(neighbourhoods[l*n+e.j], l*n+e.i)
+			}
+		}
+	}
+	h := NewSparse(layers*n, neighbourhoods)
+	return canonicalIsomorphLayered(h, n, layers, vertexClasses)
+}
+
+//canonicalIsomorphLayered finds the canonical isomorph of a graph h made from layers copies of the vertex set of a graph on n vertices. The vertex v in layer l is l*n + v.
+//The vertexClasses are repeated in each layer and the results are restricted to the first layer. The layers must be constructed so that any isomorphism preserving the layers is determined by what it does on the first layer.
+func canonicalIsomorphLayered(h Graph, n, layers int, vertexClasses [][]int) ([]int, disjoint.Set, [][]int) {
+	if vertexClasses == nil {
+		class := make([]int, n)
+		for i := range class {
+			class[i] = i
+		}
+		vertexClasses = [][]int{class}
+	}
+	layeredClasses := make([][]int, 0, layers*len(vertexClasses))
+	for l := 0; l < layers; l++ {
+		for _, class := range vertexClasses {
+			layeredClass := make([]int, len(class))
+			for i, v := range class {
+				layeredClass[i] = l*n + v
+			}
+			layeredClasses = append(layeredClasses, layeredClass)
+		}
+	}
+
+	perm, orbits, generators := CanonicalIsomorphFull(h, layeredClasses)
+	if n == 0 {
+		return perm, orbits, generators
+	}
+
+	//Every isomorphism preserves the layers so the first layer is the first n vertices of the canonical order.
+	restrictedPerm := make([]int, n)
+	copy(restrictedPerm, perm)
+
+	//The orbits are also contained in a single layer so the orbits in the first layer only refer to vertices in the first layer.
+	restrictedOrbits := make(disjoint.Set, n)
+	copy(restrictedOrbits, orbits)
+
+	restrictedGenerators := make([][]int, len(generators))
+	for i, gen := range generators {
+		restrictedGenerators[i] = make([]int, n)
+		copy(restrictedGenerators[i], gen)
+	}
+	return restrictedPerm, restrictedOrbits, restrictedGenerators
+}
@@ -1,9 +1,11 @@
 package graph_test
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
 )
 
 func TestCanonicalIsomorph(t *testing.T) {
@@ -25,6 +27,155 @@ func TestCanonicalIsomorph(t *testing.T) {
 	}
 }
 
+func TestCanonicalIsomorphVertexClasses(t *testing.T) {
+	//The path 0 - 1 - 2 - 3 with 0 in its own class has no non-trivial automorphisms.
+	g := graph.Path(4)
+	perm, orbits, generators := graph.CanonicalIsomorphFull(g, [][]int{{0}, {1, 2, 3}})
+	if perm[0] != 0 {
+		t.Errorf("Wrong permutation. Found: %v", perm)
+	}
+	if len(orbits.Sets()) != 4 || len(generators) != 0 {
+		t.Errorf("Wrong automorphisms. Found orbits: %v generators: %v", orbits.Sets(), generators)
+	}
+
+	//Graphs without edges.
+	g = graph.NewDense(5, nil)
+	perm, orbits, generators = graph.CanonicalIsomorphFull(g, [][]int{{3, 1}, {0, 2, 4}})
+	if !ints.Equal(perm, []int{3, 1, 0, 2, 4}) {
+		t.Errorf("Wrong permutation. Found: %v Expected: [3 1 0 2 4]", perm)
+	}
+	if len(orbits.Sets()) != 2 || len(generators) != 3 {
+		t.Errorf("Wrong automorphisms. Found orbits: %v generators: %v", orbits.Sets(), generators)
+	}
+
+	//Relabelling a graph while preserving the classes shouldn't change the canonical isomorph.
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 50; trial++ {
+		n := 2 + r.Intn(8)
+		edges := make([]byte, (n*(n-1))/2)
+		for i := range edges {
+			edges[i] = byte(r.Intn(2))
+		}
+		g := graph.NewDense(n, edges)
+		classes := [][]int{{}, {}}
+		for v := 0; v < n; v++ {
+			classes[v%2] = append(classes[v%2], v)
+		}
+		relabel := make([]int, n)
+		for c := range classes {
+			images := r.Perm(len(classes[c]))
+			for i, v := range classes[c] {
+				relabel[v] = classes[c][images[i]]
+			}
+		}
+		h := g.InducedSubgraph(relabel)
+		permG, _, _ := graph.CanonicalIsomorphFull(g, classes)
+		permH, _, _ := graph.CanonicalIsomorphFull(h, classes)
+		if graph.Graph6Encode(g.InducedSubgraph(permG)) != graph.Graph6Encode(h.InducedSubgraph(permH)) {
+			t.Fatalf("Canonical isomorphs differ for %v", graph.Graph6Encode(g))
+		}
+	}
+}
+
+func TestCanonicalIsomorphDigraph(t *testing.T) {
+	expectedNumber := []int{1, 1, 3, 16, 218}
+	for n := 0; n < len(expectedNumber); n++ {
+		edges := make([]byte, n*(n-1))
+		uniqueDigraphs := make(map[string]struct{})
+		for i := 0; i < (1 << uint(n*(n-1))); i++ {
+			for j := 0; j < len(edges); j++ {
+				edges[j] = byte((i >> uint(j)) & 1)
+			}
+			g := graph.NewDenseDigraph(n, edges)
+			perm, _, _ := graph.CanonicalIsomorphDigraph(g, nil)
+			uniqueDigraphs[graph.Digraph6Encode(g.InducedSubgraph(perm))] = struct{}{}
+		}
+		if len(uniqueDigraphs) != expectedNumber[n] {
+			t.Errorf("Wrong number of digraphs on %v vertices. Found: %v Expected: %v", n, len(uniqueDigraphs), expectedNumber[n])
+		}
+	}
+
+	//The directed cycle has the cyclic group as its automorphism group.
+	g := graph.NewSparseDigraph(5, nil)
+	for i := 0; i < 5; i++ {
+		g.AddEdge(i, (i+1)%5)
+	}
+	_, orbits, generators := graph.CanonicalIsomorphDigraph(g, nil)
+	if len(orbits.Sets()) != 1 {
+		t.Errorf("Wrong orbits. Found: %v Expected: 1 orbit", orbits.Sets())
+	}
+	for _, gen := range generators {
+		for i := 0; i < 5; i++ {
+			if !g.IsEdge(gen[i], gen[(i+1)%5]) {
+				t.Errorf("%v is not an automorphism", gen)
+			}
+		}
+	}
+}
+
+func TestCanonicalIsomorphEdgeColoured(t *testing.T) {
+	//There are 11 graphs on 4 vertices and so 11 colourings of K4 with 2 colours.
+	g := graph.CompleteGraph(4)
+	uniqueColourings := make(map[string]struct{})
+	for i := 0; i < 64; i++ {
+		colour := func(a, b int) int {
+			if a > b {
+				a, b = b, a
+			}
+			return (i >> uint((b*(b-1))/2+a)) & 1
+		}
+		perm, _, _ := graph.CanonicalIsomorphEdgeColoured(g, colour, nil)
+		key := make([]byte, 6)
+		for b := 1; b < 4; b++ {
+			for a := 0; a < b; a++ {
+				key[(b*(b-1))/2+a] = byte('0' + colour(perm[a], perm[b]))
+			}
+		}
+		uniqueColourings[string(key)] = struct{}{}
+	}
+	if len(uniqueColourings) != 11 {
+		t.Errorf("Wrong number of colourings. Found: %v Expected: 11", len(uniqueColourings))
+	}
+
+	//Colouring the edges of C6 alternately leaves the rotations by an even amount and the reflections through the edges.
+	c := graph.Cycle(6)
+	colour := func(a, b int) int {
+		if a > b {
+			a, b = b, a
+		}
+		if a == 0 && b == 5 {
+			return 7
+		}
+		return 3 + 4*(a%2)
+	}
+	_, orbits, generators := graph.CanonicalIsomorphEdgeColoured(c, colour, nil)
+	if sets := orbits.Sets(); len(sets) != 1 {
+		t.Errorf("Wrong orbits. Found: %v Expected: 1 orbit", sets)
+	}
+	for _, gen := range generators {
+		for i := 0; i < 6; i++ {
+			if colour(i, (i+1)%6) != colour(gen[i], gen[(i+1)%6]) {
+				t.Errorf("%v doesn't preserve the colours", gen)
+			}
+		}
+	}
+
+	//Giving one edge a third colour leaves only the reflection through that edge.
+	colour = func(a, b int) int {
+		if a > b {
+			a, b = b, a
+		}
+		if a == 0 && b == 5 {
+			return 9
+		}
+		return 3 + 4*(a%2)
+	}
+	_, orbits, _ = graph.CanonicalIsomorphEdgeColoured(c, colour, nil)
+	if sets := orbits.Sets(); len(sets) != 3 || !ints.Equal(sets[0], []int{0, 5}) || !ints.Equal(sets[1], []int{1, 4}) {
+		t.Errorf("Wrong orbits. Found: %v Expected: [[0 5] [1 4] [2 3]]", sets)
+	}
+}
+
 func BenchmarkCanonicalIsomorph(b *testing.B) {
 	graphs := []string{"S}GOOOE@?C?K?O?E_A??S?@C??_?@G??[",
 		"S{S_gOD?_A?E?E?B??O?A??G??_??w??w",
@@ -78,16 +78,17 @@ func ChromaticNumber(g Graph) (chromaticNumber int, colouring []int) {
 		pc[i] = -1
 	}
 	cn := CliqueNumber(g)
-	return dfsDsatur(g, cn, g.N()+1, pc)
+	return dfsDsatur(g, cn, g.N()+1, pc, nil)
 }
 
 //IsKColorable returns true if there is a proper colouring with k colours and an example colouring, else it returns false, nil.
+//For the more general problem of finding a homomorphism into a fixed graph, see IsHColorable.
 func IsKColorable(g Graph, k int) (ok bool, colouring []int) {
 	pc := make([]int, g.N())
 	for i := range pc {
 		pc[i] = -1
 	}
-	cn, c := dfsDsatur(g, k, k, pc)
+	cn, c := dfsDsatur(g, k, k, pc, nil)
 	if cn == -1 {
 		return false, nil
 	}
@@ -140,15 +141,25 @@ func (uh *uncolouredHeap) Pop() interface{} {
 //The serach will not consider any colourings which use more than upperBound colours (but they are allowed to use upperBound colours).
 //The search will start using the colouring partialColouring. -1 should be used for colours which aren't yet fixed.
 //If no valid colouring can be found, the returned values are -1, nil.
-func dfsDsatur(g Graph, lowerBound int, upperBound int, partialColouring []int) (chromaticNumber int, colouring []int) {
+//The options add further restrictions on the colourings and can be nil. If options.found is not nil, the search doesn't look for fewer colours and options.found is called with every colouring using at most upperBound colours instead. The returned values are then -1, nil.
+func dfsDsatur(g Graph, lowerBound int, upperBound int, partialColouring []int, options *dsaturOptions) (chromaticNumber int, colouring []int) {
+	if options == nil {
+		options = new(dsaturOptions)
+	}
 	//The code is going to assume that we already have a colouring with upperBound colours and will not look for another one.
 	//We want to find a colouring with upperBound colours if it exists or we will return -1, nil.
 	//We'll just increment upperBound by 1.
 	upperBound++
 	n := g.N()
 	if n == 0 {
+		if options.found != nil {
+			options.found([]int{})
+			return -1, nil
+		}
 		return 0, []int{}
 	}
+	//The colours can be permuted unless the vertices have different lists of colours or every colouring is wanted.
+	interchangeable := options.lists == nil && options.found == nil
 	degrees := g.Degrees()
 
 	bestColouring := make([]int, n)
@@ -168,7 +179,22 @@ func dfsDsatur(g Graph, lowerBound int, upperBound int, partialColouring []int)
 	for v, c := range partialColouring {
 		if c == -1 {
 			tmp = make([]int, upperBound)
-			uncolouredV[v] = uncolouredVertex{v, 0, tmp, degrees[v]}
+			seen := 0
+			if options.lists != nil {
+				//A colour which isn't in the list is treated as if a neighbour has it.
+				for j := range tmp {
+					tmp[j] = 1
+				}
+				for _, j := range options.lists[v] {
+					if j < upperBound {
+						tmp[j] = 0
+					}
+				}
+				for _, b := range tmp {
+					seen += b
+				}
+			}
+			uncolouredV[v] = uncolouredVertex{v, seen, tmp, degrees[v]}
 			intHeap = append(intHeap, v)
 		} else {
 			if c > maxColourUsed {
@@ -182,8 +208,12 @@ func dfsDsatur(g Graph, lowerBound int, upperBound int, partialColouring []int)
 	if maxColourUsed+1 >= upperBound {
 		return -1, nil
 	}
+	precolouredMax := maxColourUsed
 
 	for i := range uncolouredV {
+		if partialColouring[i] != -1 {
+			continue
+		}
 		seenColours := uncolouredV[i].seenColours
 		v := uncolouredV[i].v
 		for _, u := range precolouredVertices {
@@ -205,6 +235,10 @@ func dfsDsatur(g Graph, lowerBound int, upperBound int, partialColouring []int)
 	currentChoice := []int{}
 	choices := [][]int{}
 	c := make([]int, n)
+	var sizes []int
+	if options.equitable {
+		sizes = make([]int, upperBound-1)
+	}
 dfsLoop:
 	for {
 		v := -1
@@ -213,18 +247,40 @@ dfsLoop:
 			v = uh.intHeap[0]
 			vertex := uh.uv[v]
 			maxOption := upperBound - 2
-			if maxColourUsed+1 < maxOption {
+			if interchangeable && maxColourUsed+1 < maxOption {
 				maxOption = maxColourUsed + 1
 			}
+			if options.equitable {
+				for j := range sizes {
+					sizes[j] = 0
+				}
+				for _, u := range colouring {
+					if u != -1 {
+						sizes[u]++
+					}
+				}
+			}
 			for j := 0; j <= maxOption; j++ {
 				b := vertex.seenColours[j]
 				if b == 0 {
+					if options.equitable {
+						sizes[j]++
+						ok := canBeEquitable(sizes, len(uh.intHeap)-1)
+						sizes[j]--
+						if !ok {
+							continue
+						}
+					}
 					c = append(c, j)
 				}
 			}
 			if len(c) > 0 {
 				heap.Remove(&uh, 0)
 			}
+		} else if options.found != nil {
+			if !options.found(append([]int(nil), colouring...)) {
+				return -1, nil
+			}
 		} else {
 			copy(bestColouring, colouring)
 			upperBound = maxColourUsed + 1
@@ -281,7 +337,7 @@ dfsLoop:
 					currentChoice[i]++
 					colouring[chosenVertices[i]] = toColour
 
-					maxColourUsed = 0
+					maxColourUsed = precolouredMax
 					for _, u := range chosenVertices {
 						if colouring[u] > maxColourUsed {
 							maxColourUsed = colouring[u]
@@ -290,7 +346,7 @@ dfsLoop:
 					continue dfsLoop
 				}
 			}
-			if bestColouring[0] == -1 {
+			if options.found != nil || bestColouring[0] == -1 {
 				return -1, nil
 			}
 			return upperBound, bestColouring
@@ -320,76 +376,191 @@ dfsLoop:
 	}
 }
 
-//ChromaticIndex returns the minimum number of colours needed in a proper edge colouring of g (known as the Chromatic Index χ') and a colouring that uses this many colours ([1, ..., χ']).
-//The colouring is returned in the form of an edge array with 0 for non-edges and a colour in [1, 2,..., χ'] for the edges.
-//Note that a colouring with the minimum number of colours is not necessarily unique and the colouring returned here is arbitrary.
-func ChromaticIndex(g Graph) (chromaticIndex int, colouredEdges []byte) {
-	h := LineGraphDense(g)
-	ci, colouring := ChromaticNumber(h)
-	if ci == -1 {
-		return -1, nil
+//dsaturOptions contains the extra restrictions used by dfsDsatur.
+type dsaturOptions struct {
+	lists     [][]int                   //If lists is not nil, the vertex v can only have a colour in lists[v].
+	equitable bool                      //If equitable is true, the sizes of the colour classes of the colours 0, 1, ..., upperBound - 1 must differ by at most one.
+	found     func(c []int) (more bool) //If found is not nil, it is called with every colouring and the search stops if it returns false.
+}
+
+//canBeEquitable returns true if the colour classes with the given sizes can be made to differ in size by at most one by adding the given number of uncoloured vertices.
+func canBeEquitable(sizes []int, uncoloured int) bool {
+	if len(sizes) == 0 {
+		return uncoloured == 0
 	}
-	n := 0
-	colouringIndex := 0
-	colouredEdges = make([]byte, n*(n-1)/2)
-	index := 0
-	for j := 1; j < n; j++ {
-		for i := 0; i < j; i++ {
-			if g.IsEdge(i, j) {
-				colouredEdges[i] = byte(colouring[colouringIndex] + 1)
-				colouringIndex++
-			}
-			index++
+	n := uncoloured
+	for _, size := range sizes {
+		n += size
+	}
+	q, r := n/len(sizes), n%len(sizes)
+	large := 0
+	deficit := 0
+	for _, size := range sizes {
+		if size > q+1 || (size == q+1 && r == 0) {
+			return false
+		}
+		if size == q+1 {
+			large++
+		}
+		if size < q {
+			deficit += q - size
 		}
 	}
-	return ci, colouredEdges
+	return large <= r && deficit <= uncoloured
 }
 
-//ChromaticPolynomial returns the coefficients of the chromatic polynomial.
-//This is a very basic implementation.
-func ChromaticPolynomial(g EditableGraph) []int {
+//ChromaticIndex returns the minimum number of colours needed in a proper edge colouring of g (known as the Chromatic Index χ') and a colouring that uses this many colours ([0, 1, ..., χ' - 1]).
+//By Vizing's theorem, χ' is either the maximum degree Δ or Δ + 1. IsClassOne is used to check for a colouring with Δ colours and MisraGriesEdgeColouring gives a colouring with Δ + 1 colours otherwise.
+//Note that a colouring with the minimum number of colours is not necessarily unique and the colouring returned here is arbitrary.
+func ChromaticIndex(g Graph) (chromaticIndex int, colouring EdgeColouring) {
+	d := MaxDegree(g)
+	if ok, colouring := IsClassOne(g); ok {
+		return d, colouring
+	}
+	return d + 1, MisraGriesEdgeColouring(g)
+}
+
+//ColouringOptions contains the restrictions on the colourings found by AllColourings. The zero value (or a nil *ColouringOptions) allows every proper colouring with the colours 0, 1, ..., k - 1.
+type ColouringOptions struct {
+	Lists        [][]int //If Lists is not nil, the vertex v must receive a colour in Lists[v].
+	Precolouring []int   //If Precolouring is not nil, the vertex v must receive the colour Precolouring[v] unless Precolouring[v] is -1.
+	Equitable    bool    //If Equitable is true, the sizes of the colour classes of the k colours differ by at most one.
+}
+
+//colouringSearch checks the options and returns the partial colouring and the options for dfsDsatur. It returns false if the precolouring can't be extended to any colouring satisfying the options.
+func colouringSearch(g Graph, k int, options *ColouringOptions) (partialColouring []int, dsOptions *dsaturOptions, ok bool) {
+	if k < 0 {
+		panic("k must be non-negative")
+	}
+	if options == nil {
+		options = new(ColouringOptions)
+	}
 	n := g.N()
-	poly := make([]int, n+1)
-	type holder struct {
-		g    EditableGraph
-		sign int
-	}
-	toCheck := make([]holder, 1)
-	toCheck[0] = holder{g, 1}
-	var hold holder
-	for len(toCheck) > 0 {
-		hold, toCheck = toCheck[len(toCheck)-1], toCheck[:len(toCheck)-1]
-		h := hold.g
-		//Check if we know the chromatic polynomial of this graph.
-		if h.M() == 0 {
-			poly[h.N()] += hold.sign
+	dsOptions = &dsaturOptions{lists: options.Lists, equitable: options.Equitable}
+	if options.Lists != nil {
+		if len(options.Lists) != n {
+			panic("Lists does not have length equal to g.N()")
+		}
+		for _, list := range options.Lists {
+			for _, c := range list {
+				if c < 0 || c >= k {
+					panic("Lists contains a colour which isn't in [0, k - 1]")
+				}
+			}
+		}
+	}
+	partialColouring = make([]int, n)
+	for v := range partialColouring {
+		partialColouring[v] = -1
+	}
+	if options.Precolouring != nil {
+		if len(options.Precolouring) != n {
+			panic("Precolouring does not have length equal to g.N()")
+		}
+		copy(partialColouring, options.Precolouring)
+	}
+	sizes := make([]int, k)
+	uncoloured := 0
+	for v, c := range partialColouring {
+		if c < -1 || c >= k {
+			panic("Precolouring contains a colour which isn't in [-1, k - 1]")
+		}
+		if c == -1 {
+			uncoloured++
 			continue
 		}
-
-		//Choose an edge.
-		var i int
-		var j int
-	edgeLoop:
-		for i = 0; i < h.N(); i++ {
-			for j = 0; j < i; j++ {
-				if h.IsEdge(i, j) {
-					break edgeLoop
+		sizes[c]++
+		if options.Lists != nil {
+			inList := false
+			for _, d := range options.Lists[v] {
+				if d == c {
+					inList = true
 				}
 			}
+			if !inList {
+				return nil, nil, false
+			}
+		}
+		for _, u := range g.Neighbours(v) {
+			if partialColouring[u] == c {
+				return nil, nil, false
+			}
 		}
+	}
+	if options.Equitable && !canBeEquitable(sizes, uncoloured) {
+		return nil, nil, false
+	}
+	return partialColouring, dsOptions, true
+}
 
-		//Contract and delete the edge.
-		tmp := h.Copy()
-		tmp.RemoveEdge(i, j)
-		toCheck = append(toCheck, holder{tmp, hold.sign})
+//FindColouring returns true and a proper colouring of g with the colours 0, 1, ..., k - 1 which satisfies the options if there is one, else it returns false, nil. If options is nil, the default options are used.
+func FindColouring(g Graph, k int, options *ColouringOptions) (ok bool, colouring []int) {
+	partialColouring, dsOptions, ok := colouringSearch(g, k, options)
+	if !ok {
+		return false, nil
+	}
+	cn, c := dfsDsatur(g, k, k, partialColouring, dsOptions)
+	if cn == -1 {
+		return false, nil
+	}
+	return true, c
+}
 
-		tmp = h.Copy()
-		neighbours := h.Neighbours(j)
-		for _, v := range neighbours {
-			tmp.AddEdge(i, v)
+//AllColourings calls f with every proper colouring of g with the colours 0, 1, ..., k - 1 which satisfies the options until f returns false. If options is nil, the default options are used.
+//The colourings are labelled so, for example, there are 6 colourings of a triangle with 3 colours.
+func AllColourings(g Graph, k int, options *ColouringOptions, f func(colouring []int) (more bool)) {
+	partialColouring, dsOptions, ok := colouringSearch(g, k, options)
+	if !ok {
+		return
+	}
+	dsOptions.found = f
+	dfsDsatur(g, k, k, partialColouring, dsOptions)
+}
+
+//CountColourings returns the number of proper colourings of g with the colours 0, 1, ..., k - 1 which satisfy the options. If options is nil, the default options are used.
+//This enumerates the colourings so it is only suitable when there aren't too many. See ChromaticPolynomial for counting the colourings without any restrictions.
+func CountColourings(g Graph, k int, options *ColouringOptions) int {
+	count := 0
+	AllColourings(g, k, options, func(colouring []int) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+//ExtendColouring returns true and a proper colouring with the colours 0, 1, ..., k - 1 which agrees with the precolouring if there is one, else it returns false, nil. The vertex v is uncoloured in the precolouring if precolouring[v] is -1.
+func ExtendColouring(g Graph, k int, precolouring []int) (ok bool, colouring []int) {
+	return FindColouring(g, k, &ColouringOptions{Precolouring: precolouring})
+}
+
+//ListColouring returns true and a proper colouring where each vertex v receives a colour in lists[v] if there is one, else it returns false, nil. The colours must be non-negative.
+func ListColouring(g Graph, lists [][]int) (ok bool, colouring []int) {
+	k := 0
+	for _, list := range lists {
+		for _, c := range list {
+			if c+1 > k {
+				k = c + 1
+			}
+		}
+	}
+	return FindColouring(g, k, &ColouringOptions{Lists: lists})
+}
+
+//EquitableColouring returns true and a proper colouring with the colours 0, 1, ..., k - 1 where the sizes of the colour classes differ by at most one if there is one, else it returns false, nil.
+//Unlike proper colourings, a graph with an equitable k-colouring need not have an equitable (k + 1)-colouring. By the Hajnal–Szemerédi theorem, there is always an equitable k-colouring when k is larger than the maximum degree.
+func EquitableColouring(g Graph, k int) (ok bool, colouring []int) {
+	return FindColouring(g, k, &ColouringOptions{Equitable: true})
+}
+
+//EquitableChromaticNumber returns the minimum k such that g has an equitable k-colouring and an example colouring. See EquitableColouring.
+func EquitableChromaticNumber(g Graph) (int, []int) {
+	if g.N() == 0 {
+		return 0, []int{}
+	}
+	k, _ := ChromaticNumber(g)
+	for ; ; k++ {
+		if ok, colouring := EquitableColouring(g, k); ok {
+			return k, colouring
 		}
-		tmp.RemoveVertex(j)
-		toCheck = append(toCheck, holder{tmp, -hold.sign})
 	}
-	return poly
 }
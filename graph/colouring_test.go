@@ -1,11 +1,15 @@
 package graph_test
 
 import (
+	"fmt"
+	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/Tom-Johnston/mamba/graph"
 	"github.com/Tom-Johnston/mamba/graph/search"
 	"github.com/Tom-Johnston/mamba/ints"
+	"github.com/Tom-Johnston/mamba/sortints"
 )
 
 //From http://keithbriggs.info/cgt.html
@@ -120,10 +124,46 @@ func TestChromaticIndex(t *testing.T) {
 	}
 }
 
+//countColourings returns the number of proper colourings of g with k colours.
+func countColourings(g graph.Graph, k int) int64 {
+	colouring := make([]int, g.N())
+	var count func(v int) int64
+	count = func(v int) int64 {
+		if v == g.N() {
+			return 1
+		}
+		var total int64
+	colourLoop:
+		for c := 0; c < k; c++ {
+			for _, u := range g.Neighbours(v) {
+				if u < v && colouring[u] == c {
+					continue colourLoop
+				}
+			}
+			colouring[v] = c
+			total += count(v + 1)
+		}
+		return total
+	}
+	return count(0)
+}
+
+//evaluate returns the value of the polynomial with coefficients poly at x.
+func evaluate(poly []*big.Int, x int64) *big.Int {
+	value := new(big.Int)
+	for i := len(poly) - 1; i >= 0; i-- {
+		value.Mul(value, big.NewInt(x))
+		value.Add(value, poly[i])
+	}
+	return value
+}
+
 func TestChromaticPolynomial(t *testing.T) {
-	truthData := make(map[string][]int)
-	truthData["IheA@GUAo"] = []int{0, -704, 2606, -4305, 4275, -2861, 1353, -455, 105, -15, 1}
-	truthData["Dhc"] = []int{0, 4, -10, 10, -5, 1}
+	truthData := make(map[string][]int64)
+	truthData["IheA@GUAo"] = []int64{0, -704, 2606, -4305, 4275, -2861, 1353, -455, 105, -15, 1}
+	truthData["Dhc"] = []int64{0, 4, -10, 10, -5, 1}
+	truthData["?"] = []int64{1}
+	truthData["@"] = []int64{0, 1}
 	for g6 := range truthData {
 		g, err := graph.Graph6Decode(g6)
 		if err != nil {
@@ -132,8 +172,266 @@ func TestChromaticPolynomial(t *testing.T) {
 			continue
 		}
 		cp := graph.ChromaticPolynomial(g)
-		if !ints.Equal(cp, truthData[g6]) {
-			t.Fail()
+		if len(cp) != len(truthData[g6]) {
+			t.Errorf("Wrong chromatic polynomial for %v. Found: %v Expected: %v", g6, cp, truthData[g6])
+			continue
+		}
+		for i := range cp {
+			if cp[i].Int64() != truthData[g6][i] {
+				t.Errorf("Wrong chromatic polynomial for %v. Found: %v Expected: %v", g6, cp, truthData[g6])
+				break
+			}
+		}
+	}
+
+	//Compare with the number of colourings of small random graphs.
+	for seed := int64(0); seed < 100; seed++ {
+		g := graph.RandomGraph(int(3+seed%7), float64(seed%9+1)/10, seed)
+		cp := graph.ChromaticPolynomial(g)
+		if len(cp) != g.N()+1 {
+			t.Fatalf("Wrong degree for %v. Found: %v Expected: %v", graph.Graph6Encode(g), len(cp)-1, g.N())
+		}
+		for k := int64(0); k < 5; k++ {
+			if evaluate(cp, k).Int64() != countColourings(g, int(k)) {
+				t.Fatalf("Wrong number of %v-colourings of %v. Found: %v Expected: %v", k, graph.Graph6Encode(g), evaluate(cp, k), countColourings(g, int(k)))
+			}
+		}
+	}
+
+	//The chromatic polynomial of the Petersen graph is x(x - 1)(x - 2)(x^7 - 12x^6 + 67x^5 - 230x^4 + 529x^3 - 814x^2 + 775x - 352).
+	petersen := []*big.Int{big.NewInt(0), big.NewInt(1)}
+	for _, poly := range [][]int64{{-1, 1}, {-2, 1}, {-352, 775, -814, 529, -230, 67, -12, 1}} {
+		factor := make([]*big.Int, len(poly))
+		for i := range poly {
+			factor[i] = big.NewInt(poly[i])
+		}
+		product := make([]*big.Int, len(petersen)+len(factor)-1)
+		for i := range product {
+			product[i] = new(big.Int)
+		}
+		for i := range petersen {
+			for j := range factor {
+				product[i+j].Add(product[i+j], new(big.Int).Mul(petersen[i], factor[j]))
+			}
+		}
+		petersen = product
+	}
+	cp := graph.ChromaticPolynomial(graph.GeneralisedPetersenGraph(5, 2))
+	for i := range cp {
+		if cp[i].Cmp(petersen[i]) != 0 {
+			t.Fatalf("Wrong chromatic polynomial for the Petersen graph. Found: %v Expected: %v", cp, petersen)
+		}
+	}
+
+	//Check some larger graphs using the number of 3-colourings and the number of edges.
+	for _, name := range []string{"Dodecahedron", "Icosahedron", "McGee"} {
+		g, _ := graph.NamedGraph(name)
+		cp := graph.ChromaticPolynomial(g)
+		if count := countColourings(g, 3); evaluate(cp, 3).Int64() != count {
+			t.Errorf("Wrong number of 3-colourings of the %v graph. Found: %v Expected: %v", name, evaluate(cp, 3), count)
+		}
+		if cp[g.N()-1].Int64() != -int64(g.M()) {
+			t.Errorf("Wrong coefficient of x^(n - 1) for the %v graph. Found: %v Expected: %v", name, cp[g.N()-1], -g.M())
+		}
+	}
+	//Check larger random graphs using the number of edges and triangles and the chromatic number.
+	for _, params := range []struct {
+		n    int
+		p    float64
+		seed int64
+	}{{26, 0.3, 1}, {24, 0.5, 1}, {28, 0.3, 1}} {
+		g := graph.RandomGraph(params.n, params.p, params.seed)
+		n, m := g.N(), g.M()
+		cp := graph.ChromaticPolynomial(g)
+		if len(cp) != n+1 {
+			t.Fatalf("Wrong degree for %v. Found: %v Expected: %v", graph.Graph6Encode(g), len(cp)-1, n)
+		}
+		if cp[n-1].Int64() != -int64(m) {
+			t.Errorf("Wrong coefficient of x^(n - 1) for %v. Found: %v Expected: %v", graph.Graph6Encode(g), cp[n-1], -m)
+		}
+		triangles := 0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				for k := j + 1; k < n; k++ {
+					if g.IsEdge(i, j) && g.IsEdge(j, k) && g.IsEdge(i, k) {
+						triangles++
+					}
+				}
+			}
+		}
+		if expected := int64(m*(m-1)/2 - triangles); cp[n-2].Int64() != expected {
+			t.Errorf("Wrong coefficient of x^(n - 2) for %v. Found: %v Expected: %v", graph.Graph6Encode(g), cp[n-2], expected)
+		}
+		chi, _ := graph.ChromaticNumber(g)
+		if evaluate(cp, int64(chi-1)).Sign() != 0 || evaluate(cp, int64(chi)).Sign() <= 0 {
+			t.Errorf("Wrong chromatic number from the chromatic polynomial of %v. Found: P(%v) = %v and P(%v) = %v Expected: %v", graph.Graph6Encode(g), chi-1, evaluate(cp, int64(chi-1)), chi, evaluate(cp, int64(chi)), chi)
+		}
+	}
+
+	//The coefficients for K_30 don't fit in an int64.
+	cp = graph.ChromaticPolynomial(graph.CompleteGraph(30))
+	if evaluate(cp, 30).String() != "265252859812191058636308480000000" {
+		t.Errorf("Wrong number of 30-colourings of K_30. Found: %v Expected: 30!", evaluate(cp, 30))
+	}
+}
+
+func BenchmarkChromaticPolynomial(b *testing.B) {
+	graphs := []*graph.DenseGraph{graph.RandomGraph(22, 0.5, 1), graph.RandomGraph(24, 0.3, 1), graph.RandomGraph(26, 0.3, 1), graph.RandomGraph(28, 0.3, 1)}
+	for i := 0; i < b.N; i++ {
+		for _, g := range graphs {
+			graph.ChromaticPolynomial(g)
+		}
+	}
+}
+
+//properColourings returns every proper colouring of g with k colours.
+func properColourings(g graph.Graph, k int) [][]int {
+	colourings := make([][]int, 0)
+	colouring := make([]int, g.N())
+	var search func(v int)
+	search = func(v int) {
+		if v == g.N() {
+			colourings = append(colourings, append([]int(nil), colouring...))
+			return
+		}
+	colourLoop:
+		for c := 0; c < k; c++ {
+			for _, u := range g.Neighbours(v) {
+				if u < v && colouring[u] == c {
+					continue colourLoop
+				}
+			}
+			colouring[v] = c
+			search(v + 1)
+		}
+	}
+	search(0)
+	return colourings
+}
+
+//isEquitable checks if the sizes of the k colour classes differ by at most one.
+func isEquitable(colouring []int, k int) bool {
+	sizes := make([]int, k)
+	for _, c := range colouring {
+		sizes[c]++
+	}
+	return ints.Max(sizes)-ints.Min(sizes) <= 1
+}
+
+func TestColourings(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		g := graph.RandomGraph(int(3+seed%6), 0.4, seed)
+		n := g.N()
+		k := int(2 + seed%3)
+		r := rand.New(rand.NewSource(seed))
+		lists := make([][]int, n)
+		for v := range lists {
+			for c := 0; c < k; c++ {
+				if r.Intn(3) > 0 {
+					lists[v] = append(lists[v], c)
+				}
+			}
+		}
+		precolouring := make([]int, n)
+		for v := range precolouring {
+			precolouring[v] = -1
+			if r.Intn(3) == 0 {
+				precolouring[v] = r.Intn(k)
+			}
+		}
+
+		all := properColourings(g, k)
+		var listCount, precolouredCount, equitableCount, combinedCount int
+		for _, colouring := range all {
+			inLists := true
+			agrees := true
+			for v, c := range colouring {
+				if !sortints.ContainsSingle(lists[v], c) {
+					inLists = false
+				}
+				if precolouring[v] != -1 && precolouring[v] != c {
+					agrees = false
+				}
+			}
+			if inLists {
+				listCount++
+			}
+			if agrees {
+				precolouredCount++
+			}
+			if isEquitable(colouring, k) {
+				equitableCount++
+				if inLists && agrees {
+					combinedCount++
+				}
+			}
+		}
+
+		check := func(name string, options *graph.ColouringOptions, expected int) {
+			found := 0
+			seen := make(map[string]bool)
+			graph.AllColourings(g, k, options, func(colouring []int) bool {
+				if !graph.IsProperColouring(g, colouring) || seen[fmt.Sprint(colouring)] {
+					t.Fatalf("Found an invalid or repeated %v colouring %v of %v", name, colouring, graph.Graph6Encode(g))
+				}
+				seen[fmt.Sprint(colouring)] = true
+				found++
+				return true
+			})
+			if found != expected {
+				t.Errorf("Wrong number of %v colourings of %v with %v colours. Found: %v Expected: %v", name, graph.Graph6Encode(g), k, found, expected)
+			}
+		}
+		check("proper", nil, len(all))
+		check("list", &graph.ColouringOptions{Lists: lists}, listCount)
+		check("precoloured", &graph.ColouringOptions{Precolouring: precolouring}, precolouredCount)
+		check("equitable", &graph.ColouringOptions{Equitable: true}, equitableCount)
+		combined := &graph.ColouringOptions{Lists: lists, Precolouring: precolouring, Equitable: true}
+		check("combined", combined, combinedCount)
+		if ok, colouring := graph.FindColouring(g, k, combined); ok != (combinedCount > 0) || (ok && !isEquitable(colouring, k)) {
+			t.Errorf("Wrong colouring of %v with the lists %v, the precolouring %v and equal class sizes. Found: %v %v Expected: %v", graph.Graph6Encode(g), lists, precolouring, ok, colouring, combinedCount > 0)
+		}
+		//The search stops when f returns false.
+		calls := 0
+		graph.AllColourings(g, k, nil, func(colouring []int) bool {
+			calls++
+			return false
+		})
+		if calls > 1 {
+			t.Errorf("The search continued after f returned false")
+		}
+		if count := graph.CountColourings(g, k, nil); int64(count) != countColourings(g, k) {
+			t.Errorf("Wrong number of colourings of %v with %v colours. Found: %v Expected: %v", graph.Graph6Encode(g), k, count, countColourings(g, k))
+		}
+
+		ok, colouring := graph.ListColouring(g, lists)
+		if ok != (listCount > 0) || (ok && !graph.IsProperColouring(g, colouring)) {
+			t.Errorf("Wrong list colouring of %v. Found: %v %v Expected: %v", graph.Graph6Encode(g), ok, colouring, listCount > 0)
+		}
+		ok, colouring = graph.ExtendColouring(g, k, precolouring)
+		if ok != (precolouredCount > 0) || (ok && !graph.IsProperColouring(g, colouring)) {
+			t.Errorf("Wrong extension of %v for %v. Found: %v %v Expected: %v", precolouring, graph.Graph6Encode(g), ok, colouring, precolouredCount > 0)
+		}
+		ok, colouring = graph.EquitableColouring(g, k)
+		if ok != (equitableCount > 0) || (ok && (!graph.IsProperColouring(g, colouring) || !isEquitable(colouring, k))) {
+			t.Errorf("Wrong equitable colouring of %v. Found: %v %v Expected: %v", graph.Graph6Encode(g), ok, colouring, equitableCount > 0)
+		}
+	}
+
+	//K_{3, 3} is not 2-choosable.
+	lists := [][]int{{0, 1}, {0, 2}, {1, 2}, {0, 1}, {0, 2}, {1, 2}}
+	if ok, _ := graph.ListColouring(graph.CompletePartiteGraph(3, 3), lists); ok {
+		t.Errorf("Found a colouring of K_{3, 3} from the lists %v", lists)
+	}
+
+	//K_{3, 3} has an equitable 2-colouring but no equitable 3-colouring.
+	if ok, _ := graph.EquitableColouring(graph.CompletePartiteGraph(3, 3), 3); ok {
+		t.Errorf("Found an equitable 3-colouring of K_{3, 3}")
+	}
+	//The equitable chromatic number of the star K_{1, n} is ⌈n/2⌉ + 1.
+	for n := 1; n < 12; n++ {
+		if k, colouring := graph.EquitableChromaticNumber(graph.CompletePartiteGraph(1, n)); k != (n+1)/2+1 || !isEquitable(colouring, k) {
+			t.Errorf("Wrong equitable chromatic number of K_{1, %v}. Found: %v Expected: %v", n, k, (n+1)/2+1)
 		}
 	}
 }
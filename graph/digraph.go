@@ -0,0 +1,40 @@
+package graph
+
+//Digraph is the interface that represents a directed graph which cannot be copied or edited.
+//A digraph has no loops but may have both of the arcs (i, j) and (j, i).
+type Digraph interface {
+	//N() returns the number of vertices in the digraph.
+	N() int
+	//M() returns the number of arcs in the digraph.
+	M() int
+
+	//IsEdge returns true if the arc from i to j is in the digraph and false otherwise.
+	IsEdge(i, j int) bool
+	//OutNeighbours returns the vertices u such that (v, u) is an arc.
+	OutNeighbours(v int) []int
+	//InNeighbours returns the vertices u such that (u, v) is an arc.
+	InNeighbours(v int) []int
+	//OutDegrees returns the out-degree of each vertex.
+	OutDegrees() []int
+	//InDegrees returns the in-degree of each vertex.
+	InDegrees() []int
+}
+
+//EditableDigraph is the interface which represents a digraph which can be copied and edited.
+type EditableDigraph interface {
+	Digraph
+
+	//AddVertex modifies the digraph by appending one new vertex with arcs from the new vertex to the vertices in outNeighbours and arcs from the vertices in inNeighbours to the new vertex.
+	AddVertex(outNeighbours, inNeighbours []int)
+	//RemoveVertex modifies the digraph by removing the specified vertex. The index of a vertex u > v becomes u - 1 while the index of u < v is unchanged.
+	RemoveVertex(v int)
+	//AddEdge modifies the digraph by adding the arc (i, j) if it is not already present.
+	AddEdge(i, j int)
+	//RemoveEdge modifies the digraph by removing the arc (i, j) if it is present.
+	RemoveEdge(i, j int)
+
+	//InducedSubgraph returns a deep copy of the induced subdigraph of g with vertices given in order by V. This must not modify g.
+	InducedSubgraph(V []int) EditableDigraph
+	//Copy returns a deep copy of the digraph g.
+	Copy() EditableDigraph
+}
@@ -0,0 +1,242 @@
+package graph
+
+//DenseDigraph is a data structure representing a simple directed labelled graph i.e. a directed graph without loops in which each arc appears at most once.
+//DenseDigraph stores the number of vertices, the number of arcs, the in- and out-degree sequences of the digraph and stores the arcs in a []byte array which has an indicator of an arc being present.
+//The arcs are grouped by the larger of the two end vertices in the order 01, 10, 02, 20, 12, 21, 03, 30, 13, 31, 23, 32... so the arc ij with i < j is in the j*(j-1) + 2*i place and the arc ji is in the place immediately after it.
+//Adding or removing arcs are quick operations. Adding a vertex may be quick if the backing array doesn't have to grow but may require copying the entire adjacency matrix. Removing a vertex is generally slow unless it is the last vertex.
+//*DenseDigraph implements the EditableDigraph interface.
+type DenseDigraph struct {
+	NumberOfVertices  int
+	NumberOfEdges     int
+	OutDegreeSequence []int
+	InDegreeSequence  []int
+	Edges             []byte
+}
+
+//arcIndex returns the position of the arc (i, j) in the Edges of a DenseDigraph. It assumes i != j.
+func arcIndex(i, j int) int {
+	if i < j {
+		return j*(j-1) + 2*i
+	}
+	return i*(i-1) + 2*j + 1
+}
+
+//NewDenseDigraph returns a pointer to a DenseDigraph representation of the digraph with n vertices and the arcs as given in edges.
+//The arcs are in the order 01, 10, 02, 20, 12, 21, 03, 30... so the arc ij with i < j is in the j*(j-1) + 2*i place and the arc ji is in the j*(j-1) + 2*i + 1 place. The *DenseDigraph uses its own copy of edges and modifications to edges won't change the current digraph.
+//If edges is nil, the digraph with no arcs is created.
+func NewDenseDigraph(n int, edges []byte) *DenseDigraph {
+	if edges == nil {
+		edges = make([]byte, n*(n-1))
+		return &DenseDigraph{NumberOfVertices: n, NumberOfEdges: 0, OutDegreeSequence: make([]int, n), InDegreeSequence: make([]int, n), Edges: edges}
+	}
+	if len(edges) != n*(n-1) {
+		panic("Wrong number of edges")
+	}
+	outDegrees := make([]int, n)
+	inDegrees := make([]int, n)
+	m := 0
+	copyOfEdges := make([]byte, len(edges))
+	index := 0
+	for j := 0; j < n; j++ {
+		for i := 0; i < j; i++ {
+			if edges[index] > 0 {
+				copyOfEdges[index] = 1
+				outDegrees[i]++
+				inDegrees[j]++
+				m++
+			}
+			if edges[index+1] > 0 {
+				copyOfEdges[index+1] = 1
+				outDegrees[j]++
+				inDegrees[i]++
+				m++
+			}
+			index += 2
+		}
+	}
+
+	return &DenseDigraph{NumberOfVertices: n, NumberOfEdges: m, OutDegreeSequence: outDegrees, InDegreeSequence: inDegrees, Edges: copyOfEdges}
+}
+
+//N returns the number of vertices in the digraph.
+func (g DenseDigraph) N() int {
+	return g.NumberOfVertices
+}
+
+//M returns the number of arcs in the digraph.
+func (g DenseDigraph) M() int {
+	return g.NumberOfEdges
+}
+
+//IsEdge returns true if the arc (i, j) is present in the digraph and false otherwise.
+func (g DenseDigraph) IsEdge(i, j int) bool {
+	if i >= g.NumberOfVertices || j >= g.NumberOfVertices || i < 0 || j < 0 || i == j {
+		return false
+	}
+	return g.Edges[arcIndex(i, j)] > 0
+}
+
+//OutNeighbours returns the out-neighbours of v i.e. the vertices u such that (v, u) is an arc.
+func (g DenseDigraph) OutNeighbours(v int) []int {
+	r := make([]int, 0, g.OutDegreeSequence[v])
+	tmp := v * (v - 1)
+	for i := 0; i < v; i++ {
+		if g.Edges[tmp+2*i+1] > 0 {
+			r = append(r, i)
+		}
+	}
+	for i := v + 1; i < g.NumberOfVertices; i++ {
+		if g.Edges[i*(i-1)+2*v] > 0 {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
+//InNeighbours returns the in-neighbours of v i.e. the vertices u such that (u, v) is an arc.
+func (g DenseDigraph) InNeighbours(v int) []int {
+	r := make([]int, 0, g.InDegreeSequence[v])
+	tmp := v * (v - 1)
+	for i := 0; i < v; i++ {
+		if g.Edges[tmp+2*i] > 0 {
+			r = append(r, i)
+		}
+	}
+	for i := v + 1; i < g.NumberOfVertices; i++ {
+		if g.Edges[i*(i-1)+2*v+1] > 0 {
+			r = append(r, i)
+		}
+	}
+	return r
+}
+
+//OutDegrees returns the slice containing the out-degree of each vertex.
+func (g DenseDigraph) OutDegrees() []int {
+	tmpDegreeSequence := make([]int, len(g.OutDegreeSequence))
+	copy(tmpDegreeSequence, g.OutDegreeSequence)
+	return tmpDegreeSequence
+}
+
+//InDegrees returns the slice containing the in-degree of each vertex.
+func (g DenseDigraph) InDegrees() []int {
+	tmpDegreeSequence := make([]int, len(g.InDegreeSequence))
+	copy(tmpDegreeSequence, g.InDegreeSequence)
+	return tmpDegreeSequence
+}
+
+//AddEdge modifies the digraph by adding the arc (i, j) if it is not already present.
+//If the arc is already present (or i == j), this does nothing.
+func (g *DenseDigraph) AddEdge(i, j int) {
+	if i == j || g.IsEdge(i, j) {
+		return
+	}
+	g.Edges[arcIndex(i, j)] = 1
+	g.OutDegreeSequence[i]++
+	g.InDegreeSequence[j]++
+	g.NumberOfEdges++
+}
+
+//RemoveEdge modifies the digraph by removing the arc (i, j) if it is present.
+//If the arc is not already present, this does nothing.
+func (g *DenseDigraph) RemoveEdge(i, j int) {
+	if !g.IsEdge(i, j) {
+		return
+	}
+	g.Edges[arcIndex(i, j)] = 0
+	g.OutDegreeSequence[i]--
+	g.InDegreeSequence[j]--
+	g.NumberOfEdges--
+}
+
+//AddVertex modifies the digraph by appending one new vertex with arcs from the new vertex to the vertices in outNeighbours and arcs from the vertices in inNeighbours to the new vertex.
+func (g *DenseDigraph) AddVertex(outNeighbours, inNeighbours []int) {
+	n := g.NumberOfVertices
+	oldSize := n * (n - 1)
+	newSize := oldSize + 2*n
+	if cap(g.Edges) >= newSize {
+		g.Edges = g.Edges[:newSize]
+		for i := oldSize; i < newSize; i++ {
+			g.Edges[i] = 0
+		}
+	} else {
+		tmp := make([]byte, newSize)
+		copy(tmp, g.Edges)
+		g.Edges = tmp
+	}
+	g.OutDegreeSequence = append(g.OutDegreeSequence, 0)
+	g.InDegreeSequence = append(g.InDegreeSequence, 0)
+	g.NumberOfVertices++
+	for _, v := range outNeighbours {
+		g.AddEdge(n, v)
+	}
+	for _, v := range inNeighbours {
+		g.AddEdge(v, n)
+	}
+}
+
+//RemoveVertex modifies the digraph by removing the specified vertex. The index of a vertex u > v becomes u - 1 while the index of u < v is unchanged.
+func (g *DenseDigraph) RemoveVertex(v int) {
+	if v >= g.NumberOfVertices {
+		panic("No such vertex")
+	}
+
+	//Update the degree sequences and number of arcs.
+	for _, u := range g.OutNeighbours(v) {
+		g.InDegreeSequence[u]--
+	}
+	for _, u := range g.InNeighbours(v) {
+		g.OutDegreeSequence[u]--
+	}
+	g.NumberOfEdges -= g.OutDegreeSequence[v] + g.InDegreeSequence[v]
+	copy(g.OutDegreeSequence[v:], g.OutDegreeSequence[v+1:])
+	g.OutDegreeSequence = g.OutDegreeSequence[:len(g.OutDegreeSequence)-1]
+	copy(g.InDegreeSequence[v:], g.InDegreeSequence[v+1:])
+	g.InDegreeSequence = g.InDegreeSequence[:len(g.InDegreeSequence)-1]
+
+	//Update the backing array. The new position of an arc is never after the old position so this can be done in place.
+	n := g.NumberOfVertices - 1
+	for j := v; j < n; j++ {
+		oldJ := j + 1
+		for i := 0; i < j; i++ {
+			oldI := i
+			if i >= v {
+				oldI++
+			}
+			oldIndex := oldJ*(oldJ-1) + 2*oldI
+			newIndex := j*(j-1) + 2*i
+			g.Edges[newIndex] = g.Edges[oldIndex]
+			g.Edges[newIndex+1] = g.Edges[oldIndex+1]
+		}
+	}
+	g.NumberOfVertices--
+	g.Edges = g.Edges[:n*(n-1)]
+}
+
+//InducedSubgraph returns a deep copy of the induced subdigraph of g with vertices given in order by V.
+//This can also be used to return relabellings of the digraph if len(V) = g.N().
+func (g *DenseDigraph) InducedSubgraph(V []int) EditableDigraph {
+	n := len(V)
+	h := NewDenseDigraph(n, nil)
+	for j := 1; j < n; j++ {
+		for i := 0; i < j; i++ {
+			if g.IsEdge(V[i], V[j]) {
+				h.AddEdge(i, j)
+			}
+			if g.IsEdge(V[j], V[i]) {
+				h.AddEdge(j, i)
+			}
+		}
+	}
+	return h
+}
+
+//Copy returns a deep copy of the digraph g.
+func (g *DenseDigraph) Copy() EditableDigraph {
+	newEdges := make([]byte, len(g.Edges))
+	copy(newEdges, g.Edges)
+	newOutDegrees := make([]int, len(g.OutDegreeSequence))
+	copy(newOutDegrees, g.OutDegreeSequence)
+	newInDegrees := make([]int, len(g.InDegreeSequence))
+	copy(newInDegrees, g.InDegreeSequence)
+	return &DenseDigraph{NumberOfVertices: g.NumberOfVertices, NumberOfEdges: g.NumberOfEdges, OutDegreeSequence: newOutDegrees, InDegreeSequence: newInDegrees, Edges: newEdges}
+}
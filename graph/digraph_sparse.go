@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/Tom-Johnston/mamba/ints"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//SparseDigraph is a data structure for representing a simple directed graph. *SparseDigraph implements the EditableDigraph interface.
+//SparseDigraph stores the number of vertices, the number of arcs, the in- and out-degree sequences of the digraph and both the out-neighbourhood and the in-neighbourhood of each vertex.
+//As with SparseGraph, the neighbourhoods are stored as SortedInts so most modifications are slow but the digraph takes up relatively little space and returning the neighbours is quick.
+type SparseDigraph struct {
+	NumberOfVertices int
+	NumberOfEdges    int
+
+	OutNeighbourhoods []sortints.SortedInts
+	InNeighbourhoods  []sortints.SortedInts
+	OutDegreeSequence []int
+	InDegreeSequence  []int
+}
+
+//NewSparseDigraph creates the digraph on n vertices where the out-neighbours of the vertex v are given by outNeighbourhoods[v]. If outNeighbourhoods is nil, the digraph with no arcs is created.
+//The in-neighbourhoods are calculated from the out-neighbourhoods.
+func NewSparseDigraph(n int, outNeighbourhoods []sortints.SortedInts) *SparseDigraph {
+	if outNeighbourhoods == nil {
+		outNeighbourhoods = make([]sortints.SortedInts, n)
+	}
+
+	if len(outNeighbourhoods) != n {
+		panic("Length of neighbourhoods doesn't match the number of vertices.")
+	}
+
+	tmpOut := make([]sortints.SortedInts, n)
+	tmpIn := make([]sortints.SortedInts, n)
+	for i := range tmpIn {
+		tmpIn[i] = []int{}
+	}
+
+	for i := range outNeighbourhoods {
+		tmpOut[i] = sortints.NewSortedInts(outNeighbourhoods[i]...)
+		tmpOut[i].Remove(i)
+		for _, v := range tmpOut[i] {
+			//The vertices are visited in increasing order so the in-neighbourhoods stay sorted.
+			tmpIn[v] = append(tmpIn[v], i)
+		}
+	}
+
+	outDegrees := make([]int, n)
+	inDegrees := make([]int, n)
+	for i := 0; i < n; i++ {
+		outDegrees[i] = len(tmpOut[i])
+		inDegrees[i] = len(tmpIn[i])
+	}
+
+	return &SparseDigraph{NumberOfVertices: n, NumberOfEdges: ints.Sum(outDegrees), OutNeighbourhoods: tmpOut, InNeighbourhoods: tmpIn, OutDegreeSequence: outDegrees, InDegreeSequence: inDegrees}
+}
+
+//N returns the number of vertices in g.
+func (g SparseDigraph) N() int {
+	return g.NumberOfVertices
+}
+
+//M returns the number of arcs in g.
+func (g SparseDigraph) M() int {
+	return g.NumberOfEdges
+}
+
+//IsEdge returns true if the arc (i, j) is in the digraph.
+func (g SparseDigraph) IsEdge(i, j int) bool {
+	if g.OutDegreeSequence[i] < g.InDegreeSequence[j] {
+		return sortints.ContainsSingle(g.OutNeighbourhoods[i], j)
+	}
+	return sortints.ContainsSingle(g.InNeighbourhoods[j], i)
+}
+
+//OutNeighbours returns the out-neighbours of the vertex v.
+func (g SparseDigraph) OutNeighbours(v int) []int {
+	tmpNeighbours := make([]int, len(g.OutNeighbourhoods[v]))
+	copy(tmpNeighbours, g.OutNeighbourhoods[v])
+	return tmpNeighbours
+}
+
+//InNeighbours returns the in-neighbours of the vertex v.
+func (g SparseDigraph) InNeighbours(v int) []int {
+	tmpNeighbours := make([]int, len(g.InNeighbourhoods[v]))
+	copy(tmpNeighbours, g.InNeighbourhoods[v])
+	return tmpNeighbours
+}
+
+//OutDegrees returns the out-degree sequence of the digraph.
+func (g SparseDigraph) OutDegrees() []int {
+	tmpDegreeSequence := make([]int, len(g.OutDegreeSequence))
+	copy(tmpDegreeSequence, g.OutDegreeSequence)
+	return tmpDegreeSequence
+}
+
+//InDegrees returns the in-degree sequence of the digraph.
+func (g SparseDigraph) InDegrees() []int {
+	tmpDegreeSequence := make([]int, len(g.InDegreeSequence))
+	copy(tmpDegreeSequence, g.InDegreeSequence)
+	return tmpDegreeSequence
+}
+
+//AddVertex adds a vertex to the digraph with arcs to the vertices in outNeighbours and arcs from the vertices in inNeighbours.
+func (g *SparseDigraph) AddVertex(outNeighbours, inNeighbours []int) {
+	n := g.NumberOfVertices
+	g.NumberOfVertices++
+	out := sortints.NewSortedInts(outNeighbours...)
+	in := sortints.NewSortedInts(inNeighbours...)
+
+	g.NumberOfEdges += len(out) + len(in)
+
+	for _, v := range out {
+		g.InNeighbourhoods[v] = append(g.InNeighbourhoods[v], n)
+		g.InDegreeSequence[v]++
+	}
+	for _, v := range in {
+		g.OutNeighbourhoods[v] = append(g.OutNeighbourhoods[v], n)
+		g.OutDegreeSequence[v]++
+	}
+
+	g.OutNeighbourhoods = append(g.OutNeighbourhoods, out)
+	g.InNeighbourhoods = append(g.InNeighbourhoods, in)
+	g.OutDegreeSequence = append(g.OutDegreeSequence, len(out))
+	g.InDegreeSequence = append(g.InDegreeSequence, len(in))
+}
+
+//RemoveVertex removes the specified vertex. The index of a vertex u > v becomes u - 1 while the index of u < v is unchanged.
+func (g *SparseDigraph) RemoveVertex(i int) {
+	g.NumberOfVertices--
+	g.NumberOfEdges -= g.OutDegreeSequence[i] + g.InDegreeSequence[i]
+
+	for _, v := range g.OutNeighbourhoods[i] {
+		g.InNeighbourhoods[v].Remove(i)
+		g.InDegreeSequence[v]--
+	}
+	for _, v := range g.InNeighbourhoods[i] {
+		g.OutNeighbourhoods[v].Remove(i)
+		g.OutDegreeSequence[v]--
+	}
+
+	g.OutNeighbourhoods = g.OutNeighbourhoods[:i+copy(g.OutNeighbourhoods[i:], g.OutNeighbourhoods[i+1:])]
+	g.InNeighbourhoods = g.InNeighbourhoods[:i+copy(g.InNeighbourhoods[i:], g.InNeighbourhoods[i+1:])]
+	g.OutDegreeSequence = g.OutDegreeSequence[:i+copy(g.OutDegreeSequence[i:], g.OutDegreeSequence[i+1:])]
+	g.InDegreeSequence = g.InDegreeSequence[:i+copy(g.InDegreeSequence[i:], g.InDegreeSequence[i+1:])]
+
+	for j := range g.OutNeighbourhoods {
+		startIndex := sort.SearchInts(g.OutNeighbourhoods[j], i)
+		for k := startIndex; k < len(g.OutNeighbourhoods[j]); k++ {
+			g.OutNeighbourhoods[j][k]--
+		}
+		startIndex = sort.SearchInts(g.InNeighbourhoods[j], i)
+		for k := startIndex; k < len(g.InNeighbourhoods[j]); k++ {
+			g.InNeighbourhoods[j][k]--
+		}
+	}
+}
+
+//AddEdge modifies the digraph by adding the arc (i, j) if it is not already present.
+//If the arc is already present (or i == j), this does nothing.
+func (g *SparseDigraph) AddEdge(i, j int) {
+	if i == j || g.IsEdge(i, j) {
+		return
+	}
+	g.OutNeighbourhoods[i].Add(j)
+	g.InNeighbourhoods[j].Add(i)
+	g.NumberOfEdges++
+	g.OutDegreeSequence[i]++
+	g.InDegreeSequence[j]++
+}
+
+//RemoveEdge modifies the digraph by removing the arc (i, j) if it is present.
+//If the arc is not already present, this does nothing.
+func (g *SparseDigraph) RemoveEdge(i, j int) {
+	if i == j || !g.IsEdge(i, j) {
+		return
+	}
+	g.OutNeighbourhoods[i].Remove(j)
+	g.InNeighbourhoods[j].Remove(i)
+	g.NumberOfEdges--
+	g.OutDegreeSequence[i]--
+	g.InDegreeSequence[j]--
+}
+
+//InducedSubgraph returns a deep copy of the induced subdigraph of g with vertices given in order by V.
+//This can also be used to return relabellings of the digraph if len(V) = g.N().
+func (g SparseDigraph) InducedSubgraph(V []int) EditableDigraph {
+	n := len(V)
+	values, indices := intsSort(V)
+	out := make([]sortints.SortedInts, n)
+	for i, v := range V {
+		out[i] = intersectionByIndex(g.OutNeighbourhoods[v], values, indices)
+	}
+	return NewSparseDigraph(n, out)
+}
+
+//Copy returns a deep copy of the digraph g.
+func (g SparseDigraph) Copy() EditableDigraph {
+	tmpOut := make([]sortints.SortedInts, len(g.OutNeighbourhoods))
+	tmpIn := make([]sortints.SortedInts, len(g.InNeighbourhoods))
+	for i := range g.OutNeighbourhoods {
+		tmpOut[i] = make(sortints.SortedInts, len(g.OutNeighbourhoods[i]))
+		copy(tmpOut[i], g.OutNeighbourhoods[i])
+		tmpIn[i] = make(sortints.SortedInts, len(g.InNeighbourhoods[i]))
+		copy(tmpIn[i], g.InNeighbourhoods[i])
+	}
+	tmpOutDegrees := make([]int, len(g.OutDegreeSequence))
+	copy(tmpOutDegrees, g.OutDegreeSequence)
+	tmpInDegrees := make([]int, len(g.InDegreeSequence))
+	copy(tmpInDegrees, g.InDegreeSequence)
+
+	return &SparseDigraph{NumberOfVertices: g.NumberOfVertices, NumberOfEdges: g.NumberOfEdges, OutNeighbourhoods: tmpOut, InNeighbourhoods: tmpIn, OutDegreeSequence: tmpOutDegrees, InDegreeSequence: tmpInDegrees}
+}
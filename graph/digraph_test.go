@@ -0,0 +1,179 @@
+package graph_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+//randomDigraph returns a random digraph on n vertices where each arc is present with probability p.
+func randomDigraph(n int, p float64, seed int64) *graph.DenseDigraph {
+	r := rand.New(rand.NewSource(seed))
+	g := graph.NewDenseDigraph(n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i != j && r.Float64() < p {
+				g.AddEdge(i, j)
+			}
+		}
+	}
+	return g
+}
+
+func digraphsEqual(g, h graph.Digraph) bool {
+	if g.N() != h.N() || g.M() != h.M() {
+		return false
+	}
+	for i := 0; i < g.N(); i++ {
+		for j := 0; j < g.N(); j++ {
+			if g.IsEdge(i, j) != h.IsEdge(i, j) {
+				return false
+			}
+		}
+		if !ints.Equal(g.OutNeighbours(i), h.OutNeighbours(i)) || !ints.Equal(g.InNeighbours(i), h.InNeighbours(i)) {
+			return false
+		}
+	}
+	return ints.Equal(g.OutDegrees(), h.OutDegrees()) && ints.Equal(g.InDegrees(), h.InDegrees())
+}
+
+func TestDigraphs(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		n := 2 + r.Intn(10)
+		d := randomDigraph(n, 0.3, int64(trial))
+		outNeighbourhoods := make([][]int, n)
+		for v := 0; v < n; v++ {
+			outNeighbourhoods[v] = d.OutNeighbours(v)
+		}
+		s := graph.NewSparseDigraph(n, nil)
+		for v := 0; v < n; v++ {
+			for _, u := range outNeighbourhoods[v] {
+				s.AddEdge(v, u)
+			}
+		}
+		if !digraphsEqual(d, s) {
+			t.Fatalf("Sparse and dense digraphs differ after adding arcs")
+		}
+
+		//Apply the same random modifications to both.
+		for k := 0; k < 30; k++ {
+			i := r.Intn(d.N())
+			j := r.Intn(d.N())
+			switch r.Intn(4) {
+			case 0:
+				d.AddEdge(i, j)
+				s.AddEdge(i, j)
+			case 1:
+				d.RemoveEdge(i, j)
+				s.RemoveEdge(i, j)
+			case 2:
+				out := []int{i}
+				in := []int{j, i}
+				d.AddVertex(out, in)
+				s.AddVertex(out, in)
+			case 3:
+				if d.N() > 2 {
+					d.RemoveVertex(i)
+					s.RemoveVertex(i)
+				}
+			}
+			if !digraphsEqual(d, s) {
+				t.Fatalf("Sparse and dense digraphs differ after modification %v", k)
+			}
+		}
+
+		V := r.Perm(d.N())[:d.N()/2+1]
+		if !digraphsEqual(d.InducedSubgraph(V), s.InducedSubgraph(V)) {
+			t.Fatalf("Induced subdigraphs differ")
+		}
+		if !digraphsEqual(d.Copy(), s.Copy()) {
+			t.Fatalf("Copies differ")
+		}
+	}
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	for trial := 0; trial < 50; trial++ {
+		n := 1 + trial%12
+		g := randomDigraph(n, 0.15, int64(trial))
+		//Compute reachability by brute force.
+		reach := make([][]bool, n)
+		for i := 0; i < n; i++ {
+			reach[i] = make([]bool, n)
+			for j := 0; j < n; j++ {
+				reach[i][j] = i == j || graph.DirectedDistance(g, i, j) > 0
+			}
+		}
+		components := graph.StronglyConnectedComponents(g)
+		componentOf := make([]int, n)
+		count := 0
+		for c, component := range components {
+			for _, v := range component {
+				componentOf[v] = c
+				count++
+			}
+		}
+		if count != n {
+			t.Fatalf("Components don't partition the vertices: %v", components)
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if (componentOf[i] == componentOf[j]) != (reach[i][j] && reach[j][i]) {
+					t.Fatalf("Vertices %v and %v are wrongly placed: %v", i, j, components)
+				}
+				if g.IsEdge(i, j) && componentOf[j] > componentOf[i] {
+					t.Fatalf("Components are not in reverse topological order: %v", components)
+				}
+			}
+		}
+	}
+}
+
+func TestDirectedDistances(t *testing.T) {
+	//The directed cycle on 5 vertices.
+	g := graph.NewSparseDigraph(5, nil)
+	for i := 0; i < 5; i++ {
+		g.AddEdge(i, (i+1)%5)
+	}
+	if d := graph.DirectedDistance(g, 0, 4); d != 4 {
+		t.Errorf("Distance 0 to 4 - Found: %v Expected: 4", d)
+	}
+	if d := graph.DirectedDistance(g, 4, 0); d != 1 {
+		t.Errorf("Distance 4 to 0 - Found: %v Expected: 1", d)
+	}
+	if e := graph.DirectedEccentricity(g); !ints.Equal(e, []int{4, 4, 4, 4, 4}) {
+		t.Errorf("Eccentricity - Found: %v Expected: [4 4 4 4 4]", e)
+	}
+	if girth := graph.DirectedGirth(g); girth != 5 {
+		t.Errorf("Girth - Found: %v Expected: 5", girth)
+	}
+
+	g.AddEdge(3, 1)
+	if girth := graph.DirectedGirth(g); girth != 3 {
+		t.Errorf("Girth - Found: %v Expected: 3", girth)
+	}
+	g.AddEdge(0, 4)
+	if girth := graph.DirectedGirth(g); girth != 2 {
+		t.Errorf("Girth - Found: %v Expected: 2", girth)
+	}
+
+	//The transitive tournament is acyclic.
+	h := graph.NewDenseDigraph(6, nil)
+	for i := 0; i < 6; i++ {
+		for j := i + 1; j < 6; j++ {
+			h.AddEdge(i, j)
+		}
+	}
+	if girth := graph.DirectedGirth(h); girth != -1 {
+		t.Errorf("Girth - Found: %v Expected: -1", girth)
+	}
+	if e := graph.DirectedEccentricity(h); !ints.Equal(e, []int{1, -1, -1, -1, -1, -1}) {
+		t.Errorf("Eccentricity - Found: %v Expected: [1 -1 -1 -1 -1 -1]", e)
+	}
+	if c := graph.StronglyConnectedComponents(h); len(c) != 6 {
+		t.Errorf("Strongly connected components - Found: %v Expected: 6 components", c)
+	}
+}
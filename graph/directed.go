@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"container/list"
+	"sort"
+)
+
+//StronglyConnectedComponents returns the strongly connected components of the digraph g. Two vertices u and v are in the same strongly connected component if there is a directed path from u to v and a directed path from v to u.
+//Each component is sorted and the components are returned in reverse topological order i.e. if there is an arc from a vertex in component i to a vertex in component j, then j <= i.
+//This uses an iterative version of Tarjan's algorithm and runs in O(n + m).
+func StronglyConnectedComponents(g Digraph) [][]int {
+	n := g.N()
+	components := make([][]int, 0, 1)
+	if n == 0 {
+		return components
+	}
+
+	index := make([]int, n)
+	lowlink := make([]int, n)
+	for i := range index {
+		index[i] = -1
+	}
+	onStack := make([]bool, n)
+	stack := make([]int, 0, n)
+
+	//The DFS is done with an explicit stack of vertices and the position in their out-neighbourhoods.
+	type frame struct {
+		v          int
+		neighbours []int
+		next       int
+	}
+	dfs := make([]frame, 0, n)
+	counter := 0
+
+	for s := 0; s < n; s++ {
+		if index[s] != -1 {
+			continue
+		}
+		index[s] = counter
+		lowlink[s] = counter
+		counter++
+		stack = append(stack, s)
+		onStack[s] = true
+		dfs = append(dfs, frame{v: s, neighbours: g.OutNeighbours(s)})
+		for len(dfs) > 0 {
+			f := &dfs[len(dfs)-1]
+			v := f.v
+			if f.next < len(f.neighbours) {
+				u := f.neighbours[f.next]
+				f.next++
+				if index[u] == -1 {
+					index[u] = counter
+					lowlink[u] = counter
+					counter++
+					stack = append(stack, u)
+					onStack[u] = true
+					dfs = append(dfs, frame{v: u, neighbours: g.OutNeighbours(u)})
+				} else if onStack[u] && index[u] < lowlink[v] {
+					lowlink[v] = index[u]
+				}
+				continue
+			}
+
+			//We have finished with v.
+			dfs = dfs[:len(dfs)-1]
+			if len(dfs) > 0 {
+				parent := dfs[len(dfs)-1].v
+				if lowlink[v] < lowlink[parent] {
+					lowlink[parent] = lowlink[v]
+				}
+			}
+			if lowlink[v] == index[v] {
+				component := make([]int, 0, 1)
+				for {
+					u := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[u] = false
+					component = append(component, u)
+					if u == v {
+						break
+					}
+				}
+				sort.Ints(component)
+				components = append(components, component)
+			}
+		}
+	}
+	return components
+}
+
+//DirectedDistance returns the length of the shortest directed path from i to j in g and -1 if there is no such path.
+//It finds this by doing a BFS search.
+func DirectedDistance(g Digraph, i, j int) int {
+	if i == j {
+		return 0
+	}
+	n := g.N()
+	distances := make([]int, n)
+	verticesToCheck := list.New()
+	verticesToCheck.PushBack(i)
+	for verticesToCheck.Len() > 0 {
+		k := verticesToCheck.Remove(verticesToCheck.Front()).(int)
+		for _, v := range g.OutNeighbours(k) {
+			if v != i && distances[v] == 0 {
+				if v == j {
+					return distances[k] + 1
+				}
+				distances[v] = distances[k] + 1
+				verticesToCheck.PushBack(v)
+			}
+		}
+	}
+	return -1
+}
+
+//DirectedEccentricity returns a slice giving the out-eccentricity of each vertex. The out-eccentricity of a vertex v is the maximum over vertices u of the length of the shortest directed path from v to u.
+//If there is some vertex which can't be reached from v, the out-eccentricity of v is -1.
+func DirectedEccentricity(g Digraph) []int {
+	n := g.N()
+	eccentricity := make([]int, n)
+	distances := make([]int, n)
+	for i := 0; i < n; i++ {
+		e := 0
+		seenVertices := 0
+		zeroOut(distances)
+		verticesToCheck := list.New()
+		verticesToCheck.PushBack(i)
+		for verticesToCheck.Len() > 0 {
+			k := verticesToCheck.Remove(verticesToCheck.Front()).(int)
+			for _, l := range g.OutNeighbours(k) {
+				if l != i && distances[l] == 0 {
+					seenVertices++
+					tmp := distances[k] + 1
+					distances[l] = tmp
+					if tmp > e {
+						e = tmp
+					}
+					verticesToCheck.PushBack(l)
+				}
+			}
+		}
+		if seenVertices == n-1 {
+			eccentricity[i] = e
+		} else {
+			eccentricity[i] = -1
+		}
+	}
+	return eccentricity
+}
+
+//DirectedGirth returns the length of the shortest directed cycle in g and -1 if g has no directed cycles.
+//A pair of opposite arcs (i, j) and (j, i) is a directed cycle of length 2.
+//It finds the shortest cycle through each vertex v by a BFS from v which stops once it can no longer find a shorter cycle.
+func DirectedGirth(g Digraph) int {
+	n := g.N()
+	girth := n + 1
+	distances := make([]int, n)
+	verticesToCheck := list.New()
+	for i := 0; i < n; i++ {
+		for j := range distances {
+			distances[j] = -1
+		}
+		distances[i] = 0
+		verticesToCheck.Init()
+		verticesToCheck.PushBack(i)
+	bfs:
+		for verticesToCheck.Len() > 0 {
+			k := verticesToCheck.Remove(verticesToCheck.Front()).(int)
+			if distances[k]+1 >= girth {
+				break
+			}
+			for _, j := range g.OutNeighbours(k) {
+				if j == i {
+					girth = distances[k] + 1
+					break bfs
+				}
+				if distances[j] == -1 {
+					distances[j] = distances[k] + 1
+					verticesToCheck.PushBack(j)
+				}
+			}
+		}
+	}
+	if girth == n+1 {
+		return -1
+	}
+	return girth
+}
@@ -94,7 +94,7 @@ func Radius(g Graph) int {
 
 //Girth returns the size of the shortest cycle in g.
 //It finds the shortest cycle by using a BFS algorithm to find the shortest cycle containing each vertex v. This is probably not the most efficient way.
-//Directed graps are currently not supported.
+//For the length of the shortest directed cycle in a digraph, see DirectedGirth.
 func Girth(g Graph) int {
 	n := g.N()
 
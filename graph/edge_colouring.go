@@ -0,0 +1,350 @@
+package graph
+
+//EdgeColouring is a colouring of the edges of a graph. The colour of the edge uv with u < v is stored with the key [2]int{u, v} and the colours are 0, 1, 2, ....
+type EdgeColouring map[[2]int]int
+
+//Colour returns the colour of the edge uv or -1 if the edge is not coloured.
+func (ec EdgeColouring) Colour(u, v int) int {
+	if u > v {
+		u, v = v, u
+	}
+	if c, ok := ec[[2]int{u, v}]; ok {
+		return c
+	}
+	return -1
+}
+
+//NumberOfColours returns the number of distinct colours used by the colouring.
+func (ec EdgeColouring) NumberOfColours() int {
+	seen := make(map[int]bool)
+	for _, c := range ec {
+		seen[c] = true
+	}
+	return len(seen)
+}
+
+//IsProperEdgeColouring checks if the colouring gives every edge of g a colour which is at least 0, no two edges with a common vertex have the same colour and there are no colours for pairs which are not edges of g.
+func IsProperEdgeColouring(g Graph, colouring EdgeColouring) bool {
+	if len(colouring) != g.M() {
+		return false
+	}
+	for e, c := range colouring {
+		if c < 0 || e[0] >= e[1] || e[0] < 0 || e[1] >= g.N() || !g.IsEdge(e[0], e[1]) {
+			return false
+		}
+	}
+	seen := make(map[int]bool)
+	for v := 0; v < g.N(); v++ {
+		for k := range seen {
+			delete(seen, k)
+		}
+		for _, u := range g.Neighbours(v) {
+			c := colouring.Colour(u, v)
+			if seen[c] {
+				return false
+			}
+			seen[c] = true
+		}
+	}
+	return true
+}
+
+//MisraGriesEdgeColouring returns a proper edge colouring of g with at most Δ + 1 colours where Δ is the maximum degree of g. By Vizing's theorem, the chromatic index is Δ or Δ + 1 so the colouring uses at most one more colour than necessary.
+//This uses the algorithm of Misra and Gries which colours the edges one at a time by building a fan at one end of the edge, swapping the colours on an alternating path and rotating the fan. This takes O(nmΔ) time in the worst case.
+func MisraGriesEdgeColouring(g Graph) EdgeColouring {
+	n := g.N()
+	k := MaxDegree(g) + 1
+	//at[v*k + c] is the neighbour u of v such that uv has colour c or -1 if no edge at v has colour c.
+	at := make([]int, n*k)
+	for i := range at {
+		at[i] = -1
+	}
+	setColour := func(u, v, c int) {
+		at[u*k+c] = v
+		at[v*k+c] = u
+	}
+	colour := func(u, v int) int {
+		for c := 0; c < k; c++ {
+			if at[u*k+c] == v {
+				return c
+			}
+		}
+		return -1
+	}
+	free := func(v int) int {
+		for c := 0; c < k; c++ {
+			if at[v*k+c] == -1 {
+				return c
+			}
+		}
+		return -1
+	}
+
+	//inFan[v] == stamp if v is in the current fan.
+	inFan := make([]int, n)
+	stamp := 0
+	fan := make([]int, 0, k)
+	path := make([][3]int, 0)
+	for x := 0; x < n; x++ {
+		for _, y := range g.Neighbours(x) {
+			if y < x {
+				continue
+			}
+			//Build a maximal fan at x starting with y: the edges xf[i] for i > 0 are coloured and the colour of xf[i] is free on f[i - 1].
+			stamp++
+			fan = append(fan[:0], y)
+			inFan[y] = stamp
+		fanLoop:
+			for {
+				last := fan[len(fan)-1]
+				for c := 0; c < k; c++ {
+					if at[last*k+c] == -1 {
+						if z := at[x*k+c]; z != -1 && inFan[z] != stamp {
+							fan = append(fan, z)
+							inFan[z] = stamp
+							continue fanLoop
+						}
+					}
+				}
+				break
+			}
+			c := free(x)
+			d := free(fan[len(fan)-1])
+
+			//Invert the cd-path starting at x so that d is free on x.
+			path = path[:0]
+			for v, col := x, d; at[v*k+col] != -1; {
+				u := at[v*k+col]
+				path = append(path, [3]int{v, u, col})
+				v = u
+				if col == d {
+					col = c
+				} else {
+					col = d
+				}
+			}
+			for _, e := range path {
+				at[e[0]*k+e[2]] = -1
+				at[e[1]*k+e[2]] = -1
+			}
+			for _, e := range path {
+				if e[2] == d {
+					setColour(e[0], e[1], c)
+				} else {
+					setColour(e[0], e[1], d)
+				}
+			}
+
+			//Find the first w in the fan with d free on w. The fan up to w is still a fan after inverting the path.
+			w := 0
+			for at[fan[w]*k+d] != -1 {
+				w++
+			}
+			//Rotate the fan up to w and colour xw with d.
+			for i := 0; i < w; i++ {
+				col := colour(x, fan[i+1])
+				at[fan[i+1]*k+col] = -1
+				setColour(x, fan[i], col)
+			}
+			setColour(x, fan[w], d)
+		}
+	}
+
+	colouring := make(EdgeColouring, g.M())
+	for v := 0; v < n; v++ {
+		for c := 0; c < k; c++ {
+			if u := at[v*k+c]; u > v {
+				colouring[[2]int{v, u}] = c
+			}
+		}
+	}
+	return colouring
+}
+
+//IsClassOne returns true and a proper edge colouring with Δ colours if the chromatic index of g is equal to the maximum degree Δ, otherwise it returns false, nil. Graphs with chromatic index Δ are called class one and graphs with chromatic index Δ + 1 are called class two.
+//The colouring from MisraGriesEdgeColouring is tried first. If it uses Δ + 1 colours, the vertices are added one at a time while keeping track of the possible colourings of the edges between the added vertices and the remaining vertices. This takes time linear in the number of vertices for graphs such as the flower snarks where there are always few such edges. If there are too many possible colourings of these edges, the line graph is searched for a colouring with Δ colours instead where the edges at a vertex of maximum degree are coloured first since they must have distinct colours.
+func IsClassOne(g Graph) (bool, EdgeColouring) {
+	n := g.N()
+	d := MaxDegree(g)
+	colouring := MisraGriesEdgeColouring(g)
+	if colouring.NumberOfColours() <= d {
+		//Relabel the colours to be 0, 1, ..., d - 1.
+		relabel := make(map[int]int)
+		for e, c := range colouring {
+			if _, ok := relabel[c]; !ok {
+				relabel[c] = len(relabel)
+			}
+			colouring[e] = relabel[c]
+		}
+		return true, colouring
+	}
+
+	if colouring, completed := frontierEdgeColouring(g, d, maxFrontierStates); completed {
+		return colouring != nil, colouring
+	}
+
+	//The vertices of the line graph are the edges ij with i < j in order of j and then i.
+	edges := make([][2]int, 0, g.M())
+	for j := 0; j < n; j++ {
+		for i := 0; i < j; i++ {
+			if g.IsEdge(i, j) {
+				edges = append(edges, [2]int{i, j})
+			}
+		}
+	}
+	v := 0
+	degrees := g.Degrees()
+	for u := 1; u < n; u++ {
+		if degrees[u] > degrees[v] {
+			v = u
+		}
+	}
+	partialColouring := make([]int, len(edges))
+	c := 0
+	for i, e := range edges {
+		partialColouring[i] = -1
+		if e[0] == v || e[1] == v {
+			partialColouring[i] = c
+			c++
+		}
+	}
+	if cn, lineColouring := dfsDsatur(LineGraphDense(g), d, d, partialColouring, nil); cn != -1 {
+		colouring = make(EdgeColouring, len(edges))
+		for i, e := range edges {
+			colouring[e] = lineColouring[i]
+		}
+		return true, colouring
+	}
+	return false, nil
+}
+
+//maxFrontierStates is the maximum number of partial colourings which frontierEdgeColouring stores before giving up.
+const maxFrontierStates = 1 << 18
+
+//frontierEdgeColouring looks for a proper edge colouring of g with k colours. The vertices are added one at a time and the set of possible colourings of the frontier (the edges between the added vertices and the remaining vertices) is stored.
+//This returns a colouring and true if there is a colouring, nil and true if there is no colouring and nil and false if more than maxStates colourings of the frontier are stored in total.
+func frontierEdgeColouring(g Graph, k, maxStates int) (EdgeColouring, bool) {
+	n := g.N()
+	//Choose the vertex with the most added neighbours and then the fewest remaining neighbours to keep the frontier small.
+	order := make([]int, 0, n)
+	added := make([]bool, n)
+	addedNeighbours := make([]int, n)
+	degrees := g.Degrees()
+	for len(order) < n {
+		best := -1
+		for v := 0; v < n; v++ {
+			if added[v] {
+				continue
+			}
+			if best == -1 || addedNeighbours[v] > addedNeighbours[best] || (addedNeighbours[v] == addedNeighbours[best] && degrees[v] < degrees[best]) {
+				best = v
+			}
+		}
+		added[best] = true
+		order = append(order, best)
+		for _, u := range g.Neighbours(best) {
+			addedNeighbours[u]++
+		}
+	}
+
+	//The state is the colours of the frontier edges in order and parents[i][j] is the index of the state before adding the ith vertex which gave the jth state after adding it.
+	frontiers := make([][][2]int, n)
+	states := make([][]string, n)
+	parents := make([][]int, n)
+	frontier := [][2]int{}
+	previous := []string{""}
+	for i := range added {
+		added[i] = false
+	}
+	total := 0
+	used := make([]bool, k)
+	for i, v := range order {
+		incoming := make([]int, 0)
+		keep := make([]int, 0, len(frontier))
+		for j, e := range frontier {
+			if e[1] == v {
+				incoming = append(incoming, j)
+			} else {
+				keep = append(keep, j)
+			}
+		}
+		added[v] = true
+		newFrontier := make([][2]int, 0, len(keep))
+		for _, j := range keep {
+			newFrontier = append(newFrontier, frontier[j])
+		}
+		outgoing := len(newFrontier)
+		for _, u := range g.Neighbours(v) {
+			if !added[u] {
+				newFrontier = append(newFrontier, [2]int{v, u})
+			}
+		}
+
+		seen := make(map[string]bool)
+		buf := make([]byte, len(newFrontier))
+		var assign func(p, x int) bool
+		assign = func(p, x int) bool {
+			if x == len(newFrontier) {
+				key := string(buf)
+				if !seen[key] {
+					seen[key] = true
+					states[i] = append(states[i], key)
+					parents[i] = append(parents[i], p)
+					total++
+				}
+				return total <= maxStates
+			}
+			for c := 0; c < k; c++ {
+				//The colours of the edges at the first vertex can be chosen in any order.
+				if !used[c] && (i > 0 || c == x) {
+					used[c] = true
+					buf[x] = byte(c)
+					ok := assign(p, x+1)
+					used[c] = false
+					if !ok {
+						return false
+					}
+				}
+			}
+			return true
+		}
+	stateLoop:
+		for p, state := range previous {
+			for c := range used {
+				used[c] = false
+			}
+			for _, j := range incoming {
+				if used[state[j]] {
+					continue stateLoop
+				}
+				used[state[j]] = true
+			}
+			for x, j := range keep {
+				buf[x] = state[j]
+			}
+			if !assign(p, outgoing) {
+				return nil, false
+			}
+		}
+		if len(states[i]) == 0 {
+			return nil, true
+		}
+		frontiers[i] = newFrontier
+		frontier = newFrontier
+		previous = states[i]
+	}
+
+	colouring := make(EdgeColouring, g.M())
+	index := 0
+	for i := n - 1; i >= 0; i-- {
+		state := states[i][index]
+		for x, e := range frontiers[i] {
+			if e[0] > e[1] {
+				e[0], e[1] = e[1], e[0]
+			}
+			colouring[e] = int(state[x])
+		}
+		index = parents[i][index]
+	}
+	return colouring, true
+}
@@ -0,0 +1,67 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+func TestMisraGriesEdgeColouring(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		var g graph.Graph
+		if seed%2 == 0 {
+			g = graph.RandomGraph(int(5+seed), 0.4, seed)
+		} else {
+			g = graph.RandomRegularGraph(int(10+2*seed), int(3+seed%5), seed)
+		}
+		colouring := graph.MisraGriesEdgeColouring(g)
+		if !graph.IsProperEdgeColouring(g, colouring) {
+			t.Fatalf("Found an improper edge colouring of %v", graph.Graph6Encode(g))
+		}
+		if colouring.NumberOfColours() > graph.MaxDegree(g)+1 {
+			t.Errorf("Used %v colours for %v. Expected at most: %v", colouring.NumberOfColours(), graph.Graph6Encode(g), graph.MaxDegree(g)+1)
+		}
+	}
+}
+
+func TestIsClassOne(t *testing.T) {
+	check := func(name string, g graph.Graph, expected bool) {
+		classOne, colouring := graph.IsClassOne(g)
+		if classOne != expected {
+			t.Errorf("Wrong class for %v. Found: %v Expected: %v", name, classOne, expected)
+			return
+		}
+		if classOne && (!graph.IsProperEdgeColouring(g, colouring) || colouring.NumberOfColours() > graph.MaxDegree(g)) {
+			t.Errorf("Found an invalid colouring of %v", name)
+		}
+	}
+
+	//Snarks are class two by definition.
+	check("Petersen graph", graph.GeneralisedPetersenGraph(5, 2), false)
+	for n := 3; n < 100; n += 2 {
+		check("flower snark", graph.FlowerSnark(n), false)
+	}
+	//Bipartite graphs, even complete graphs and even cycles are class one while odd complete graphs and odd cycles are class two.
+	for n := 2; n < 9; n++ {
+		check("complete graph", graph.CompleteGraph(n), n%2 == 0)
+	}
+	for n := 3; n < 12; n++ {
+		check("cycle", graph.Cycle(n), n%2 == 0)
+	}
+	check("hypercube", graph.HypercubeGraph(5), true)
+	for seed := int64(0); seed < 10; seed++ {
+		check("random bipartite graph", graph.RandomBipartiteGraph(10, 12, 0.5, seed), true)
+	}
+	//The generalised Petersen graphs other than the Petersen graph are class one.
+	check("Dürer graph", graph.GeneralisedPetersenGraph(6, 2), true)
+	check("Desargues graph", graph.GeneralisedPetersenGraph(10, 3), true)
+
+	//The chromatic index agrees with the colouring.
+	for seed := int64(0); seed < 20; seed++ {
+		g := graph.RandomGraph(12, 0.3, seed)
+		chromaticIndex, colouring := graph.ChromaticIndex(g)
+		if !graph.IsProperEdgeColouring(g, colouring) || colouring.NumberOfColours() > chromaticIndex {
+			t.Errorf("Found an invalid colouring of %v", graph.Graph6Encode(g))
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package graph
+
+//EmbeddingOptions contains the options for finding embeddings of a pattern graph into a target graph. The zero value (or a nil *EmbeddingOptions) finds all (not necessarily induced) embeddings without any restriction on where each vertex is mapped.
+type EmbeddingOptions struct {
+	Induced        bool    //If Induced is true, non-edges of the pattern must be mapped to non-edges of the target so the image of the pattern is an induced subgraph.
+	PatternClasses [][]int //If PatternClasses and TargetClasses are not nil, the vertices in PatternClasses[i] must be mapped to vertices in TargetClasses[i]. Every vertex of the pattern must appear in exactly one class and the two slices must have the same length.
+	TargetClasses  [][]int //Vertices of the target which don't appear in any class are never used.
+}
+
+//EmbeddingIterator iterates over the embeddings of a pattern graph into a target graph. It should be initialised with Embeddings.
+//An embedding is an injective map f from the vertices of the pattern to the vertices of the target such that f(u)f(v) is an edge of the target whenever uv is an edge of the pattern.
+//An EmbeddingIterator is not safe for concurrent use by multiple goroutines.
+type EmbeddingIterator struct {
+	target        Graph
+	targetDegrees []int
+	patternDegree []int //The degree in the pattern of the vertex in each position of the order.
+	targetClass   []int //The class of each target vertex or -1 if it isn't in a class.
+	patternClass  []int //The class of the vertex in each position of the order.
+	classMembers  [][]int
+
+	order        []int   //The order in which the vertices of the pattern are mapped.
+	parent       []int   //The position in the order of an earlier neighbour or -1 if there isn't one.
+	edgesBack    [][]int //The earlier positions adjacent to each position.
+	nonEdgesBack [][]int //The earlier positions not adjacent to each position. This is only used for induced embeddings.
+
+	images     []int //The image of the vertex in each position of the order.
+	used       []bool
+	candidates [][]int
+	pos        []int
+	depth      int
+	done       bool
+}
+
+//Embeddings returns an *EmbeddingIterator which iterates over the embeddings of the pattern h into the target g. If options is nil, the default options are used.
+//The embeddings are labelled so, for example, there are 6 embeddings of a triangle into a triangle.
+//The search is a backtracking search in the style of VF2. The vertices of the pattern are ordered so that each vertex has as many earlier neighbours as possible and each vertex is only mapped to the neighbours of the image of an earlier neighbour.
+func Embeddings(h, g Graph, options *EmbeddingOptions) *EmbeddingIterator {
+	if options == nil {
+		options = new(EmbeddingOptions)
+	}
+	k := h.N()
+	n := g.N()
+	it := &EmbeddingIterator{target: g, targetDegrees: g.Degrees()}
+
+	//Record the classes.
+	patternClassOf := make([]int, k)
+	it.targetClass = make([]int, n)
+	if options.PatternClasses != nil || options.TargetClasses != nil {
+		if len(options.PatternClasses) != len(options.TargetClasses) {
+			panic("The pattern and the target have a different number of vertex classes.")
+		}
+		for i := range patternClassOf {
+			patternClassOf[i] = -1
+		}
+		for i := range it.targetClass {
+			it.targetClass[i] = -1
+		}
+		for c, class := range options.PatternClasses {
+			for _, v := range class {
+				if patternClassOf[v] != -1 {
+					panic("A vertex of the pattern is in more than one class.")
+				}
+				patternClassOf[v] = c
+			}
+		}
+		for _, c := range patternClassOf {
+			if c == -1 {
+				panic("A vertex of the pattern is not in any class.")
+			}
+		}
+		for c, class := range options.TargetClasses {
+			for _, v := range class {
+				it.targetClass[v] = c
+			}
+		}
+		it.classMembers = options.TargetClasses
+	} else {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		it.classMembers = [][]int{all}
+	}
+
+	//Choose the order of the vertices of the pattern. The next vertex is the one with the most neighbours already in the order, breaking ties by the degree.
+	hNeighbours := make([][]int, k)
+	for v := range hNeighbours {
+		hNeighbours[v] = h.Neighbours(v)
+	}
+	hDegrees := h.Degrees()
+	positionOf := make([]int, k)
+	for i := range positionOf {
+		positionOf[i] = -1
+	}
+	orderedNeighbours := make([]int, k)
+	it.order = make([]int, 0, k)
+	for len(it.order) < k {
+		best := -1
+		for v := 0; v < k; v++ {
+			if positionOf[v] != -1 {
+				continue
+			}
+			if best == -1 || orderedNeighbours[v] > orderedNeighbours[best] || (orderedNeighbours[v] == orderedNeighbours[best] && hDegrees[v] > hDegrees[best]) {
+				best = v
+			}
+		}
+		positionOf[best] = len(it.order)
+		it.order = append(it.order, best)
+		for _, u := range hNeighbours[best] {
+			orderedNeighbours[u]++
+		}
+	}
+
+	it.parent = make([]int, k)
+	it.edgesBack = make([][]int, k)
+	it.nonEdgesBack = make([][]int, k)
+	it.patternDegree = make([]int, k)
+	it.patternClass = make([]int, k)
+	for d, v := range it.order {
+		it.parent[d] = -1
+		it.patternDegree[d] = hDegrees[v]
+		it.patternClass[d] = patternClassOf[v]
+		for _, u := range hNeighbours[v] {
+			if p := positionOf[u]; p < d {
+				it.edgesBack[d] = append(it.edgesBack[d], p)
+				if it.parent[d] == -1 || p < it.parent[d] {
+					it.parent[d] = p
+				}
+			}
+		}
+		if options.Induced {
+			for p := 0; p < d; p++ {
+				if !h.IsEdge(v, it.order[p]) {
+					it.nonEdgesBack[d] = append(it.nonEdgesBack[d], p)
+				}
+			}
+		}
+	}
+
+	it.images = make([]int, k)
+	for i := range it.images {
+		it.images[i] = -1
+	}
+	it.used = make([]bool, n)
+	it.candidates = make([][]int, k)
+	it.pos = make([]int, k)
+	if k > n {
+		it.done = true
+	} else if k > 0 {
+		it.candidates[0] = it.candidateList(0)
+	}
+	return it
+}
+
+//candidateList returns the possible images of the vertex in position d of the order.
+func (it *EmbeddingIterator) candidateList(d int) []int {
+	if p := it.parent[d]; p != -1 {
+		return it.target.Neighbours(it.images[p])
+	}
+	return it.classMembers[it.patternClass[d]]
+}
+
+//feasible returns true if the vertex in position d can be mapped to v given the images of the earlier vertices.
+func (it *EmbeddingIterator) feasible(d, v int) bool {
+	if it.used[v] || it.targetDegrees[v] < it.patternDegree[d] || it.targetClass[v] != it.patternClass[d] {
+		return false
+	}
+	for _, p := range it.edgesBack[d] {
+		if !it.target.IsEdge(v, it.images[p]) {
+			return false
+		}
+	}
+	for _, p := range it.nonEdgesBack[d] {
+		if it.target.IsEdge(v, it.images[p]) {
+			return false
+		}
+	}
+	return true
+}
+
+//Next finds the next embedding and returns true if there is one.
+func (it *EmbeddingIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	k := len(it.order)
+	if k == 0 {
+		//There is exactly one embedding of the empty graph.
+		it.done = true
+		return true
+	}
+	for it.depth >= 0 {
+		d := it.depth
+		if v := it.images[d]; v != -1 {
+			it.used[v] = false
+			it.images[d] = -1
+		}
+		found := false
+		for it.pos[d] < len(it.candidates[d]) {
+			v := it.candidates[d][it.pos[d]]
+			it.pos[d]++
+			if it.feasible(d, v) {
+				it.images[d] = v
+				it.used[v] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			it.depth--
+			continue
+		}
+		if d == k-1 {
+			return true
+		}
+		it.depth++
+		it.candidates[it.depth] = it.candidateList(it.depth)
+		it.pos[it.depth] = 0
+	}
+	it.done = true
+	return false
+}
+
+//Value returns the current embedding as a slice f where f[v] is the image of the vertex v of the pattern.
+func (it *EmbeddingIterator) Value() []int {
+	f := make([]int, len(it.order))
+	for d, v := range it.order {
+		f[v] = it.images[d]
+	}
+	return f
+}
+
+//FindEmbedding returns an embedding of the pattern h into the target g or nil if there is no such embedding. If options is nil, the default options are used.
+//The embedding is a slice f where f[v] is the image of the vertex v of h. See Embeddings for more details.
+func FindEmbedding(h, g Graph, options *EmbeddingOptions) []int {
+	it := Embeddings(h, g, options)
+	if it.Next() {
+		return it.Value()
+	}
+	return nil
+}
+
+//CountEmbeddings returns the number of embeddings of the pattern h into the target g. If options is nil, the default options are used.
+//The embeddings are labelled so this is the number of copies of h in g multiplied by the number of automorphisms of h. See Embeddings for more details.
+func CountEmbeddings(h, g Graph, options *EmbeddingOptions) int {
+	it := Embeddings(h, g, options)
+	count := 0
+	for it.Next() {
+		count++
+	}
+	return count
+}
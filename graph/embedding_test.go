@@ -0,0 +1,154 @@
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/graph/search"
+)
+
+//randomGraph returns a random graph on n vertices where each edge is present with probability p.
+func randomGraph(n int, p float64, r *rand.Rand) *graph.DenseGraph {
+	g := graph.NewDense(n, nil)
+	for j := 1; j < n; j++ {
+		for i := 0; i < j; i++ {
+			if r.Float64() < p {
+				g.AddEdge(i, j)
+			}
+		}
+	}
+	return g
+}
+
+//bruteForceEmbeddings counts the embeddings of h into g by checking every injective map.
+func bruteForceEmbeddings(h, g graph.Graph, induced bool) int {
+	f := make([]int, h.N())
+	used := make([]bool, g.N())
+	var count func(v int) int
+	count = func(v int) int {
+		if v == h.N() {
+			return 1
+		}
+		total := 0
+		for x := 0; x < g.N(); x++ {
+			if used[x] {
+				continue
+			}
+			ok := true
+			for u := 0; u < v; u++ {
+				if h.IsEdge(u, v) && !g.IsEdge(f[u], x) || induced && !h.IsEdge(u, v) && g.IsEdge(f[u], x) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				f[v] = x
+				used[x] = true
+				total += count(v + 1)
+				used[x] = false
+			}
+		}
+		return total
+	}
+	return count(0)
+}
+
+func TestEmbeddings(t *testing.T) {
+	k4 := graph.CompleteGraph(4)
+	tests := []struct {
+		h, g     graph.Graph
+		induced  bool
+		expected int
+	}{
+		{graph.CompleteGraph(3), k4, false, 24},
+		{graph.Cycle(4), k4, false, 24},
+		{graph.Cycle(4), k4, true, 0},
+		{graph.NewDense(0, nil), k4, false, 1},
+		{graph.Path(3), graph.Cycle(5), true, 10},
+		{k4, graph.CompleteGraph(3), false, 0},
+	}
+	for _, test := range tests {
+		options := &graph.EmbeddingOptions{Induced: test.induced}
+		if c := graph.CountEmbeddings(test.h, test.g, options); c != test.expected {
+			t.Errorf("Wrong number of embeddings of %v into %v. Found: %v Expected: %v", graph.Graph6Encode(test.h), graph.Graph6Encode(test.g), c, test.expected)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		h := randomGraph(1+r.Intn(5), 0.5, r)
+		g := randomGraph(h.N()+r.Intn(4), 0.5, r)
+		for _, induced := range []bool{false, true} {
+			options := &graph.EmbeddingOptions{Induced: induced}
+			expected := bruteForceEmbeddings(h, g, induced)
+			if c := graph.CountEmbeddings(h, g, options); c != expected {
+				t.Fatalf("Wrong number of embeddings of %v into %v (induced: %v). Found: %v Expected: %v", graph.Graph6Encode(h), graph.Graph6Encode(g), induced, c, expected)
+			}
+			f := graph.FindEmbedding(h, g, options)
+			if (f == nil) != (expected == 0) {
+				t.Fatalf("FindEmbedding disagrees with the count")
+			}
+			//Check every embedding is valid and distinct.
+			seen := make(map[string]bool)
+			it := graph.Embeddings(h, g, options)
+			for it.Next() {
+				f := it.Value()
+				seen[fmt.Sprint(f)] = true
+				for j := 0; j < h.N(); j++ {
+					for i := 0; i < j; i++ {
+						if h.IsEdge(i, j) && !g.IsEdge(f[i], f[j]) || induced && !h.IsEdge(i, j) && g.IsEdge(f[i], f[j]) {
+							t.Fatalf("%v is not an embedding", f)
+						}
+					}
+				}
+			}
+			if len(seen) != expected {
+				t.Fatalf("Found %v distinct embeddings. Expected: %v", len(seen), expected)
+			}
+		}
+	}
+}
+
+func TestEmbeddingsVertexClasses(t *testing.T) {
+	//Map the path 0 - 1 - 2 into the cycle 0 - 1 - 2 - 3 - 4 - 5 with the middle vertex going to an even vertex and the ends going to odd vertices.
+	options := &graph.EmbeddingOptions{
+		PatternClasses: [][]int{{1}, {0, 2}},
+		TargetClasses:  [][]int{{0, 2, 4}, {1, 3, 5}},
+	}
+	if c := graph.CountEmbeddings(graph.Path(3), graph.Cycle(6), options); c != 6 {
+		t.Errorf("Wrong number of embeddings. Found: %v Expected: 6", c)
+	}
+	it := graph.Embeddings(graph.Path(3), graph.Cycle(6), options)
+	for it.Next() {
+		if f := it.Value(); f[1]%2 != 0 || f[0]%2 != 1 || f[2]%2 != 1 {
+			t.Errorf("%v doesn't respect the classes", f)
+		}
+	}
+
+	//The other way round is impossible in the path on 4 vertices where the vertex 3 is not in a class.
+	options = &graph.EmbeddingOptions{
+		PatternClasses: [][]int{{1}, {0, 2}},
+		TargetClasses:  [][]int{{0, 2}, {1}},
+	}
+	if f := graph.FindEmbedding(graph.Path(3), graph.Path(4), options); f != nil {
+		t.Errorf("Found the embedding %v but expected none", f)
+	}
+}
+
+func TestEmbeddingsPruning(t *testing.T) {
+	//There are 38 triangle-free graphs on 6 vertices.
+	triangle := graph.CompleteGraph(3)
+	prune := func(g *graph.DenseGraph) bool {
+		return graph.FindEmbedding(triangle, g, nil) != nil
+	}
+	iter := search.WithPruning(6, 0, 1, func(g *graph.DenseGraph) bool { return false }, prune)
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	if count != 38 {
+		t.Errorf("Wrong number of triangle-free graphs. Found: %v Expected: 38", count)
+	}
+}
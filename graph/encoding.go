@@ -6,6 +6,8 @@ import (
 	"math"
 	"math/bits"
 	"strings"
+
+	"github.com/Tom-Johnston/mamba/sortints"
 )
 
 const maxUint = ^uint(0)
@@ -26,32 +28,17 @@ func Graph6Decode(s string) (*DenseGraph, error) {
 		}
 	}
 
-	var n uint64
-	i := 0
-
 	if len(s) == 0 {
 		return NewDense(0, nil), nil
 	}
 
-	if s[0] != 126 {
-		n = uint64(s[0] - 63)
-		i = 1
-	} else if s[1] != 126 {
-		if len(s) < 4 {
-			return &DenseGraph{}, errors.New("String too short - unable to decode n")
-		}
-		n = (uint64(s[1]-63) << 12) + (uint64(s[2]-63) << 6) + uint64(s[3]-63)
-		i = 4
-	} else {
-		if len(s) < 8 {
-			return &DenseGraph{}, errors.New("String too short - unable to decode n")
-		}
-		n = (uint64(s[2]-63) << 30) + (uint64(s[3]-63) << 24) + (uint64(s[4]-63) << 18) + (uint64(s[5]-63) << 12) + (uint64(s[6]-63) << 6) + uint64(s[7]-63)
-		i = 8
-		MaxN := 0.5 + math.Sqrt(2*float64(maxInt)+0.25)
-		if float64(n) > MaxN {
-			return &DenseGraph{}, errors.New("Graph too large")
-		}
+	n, i, err := decodeSize(s)
+	if err != nil {
+		return &DenseGraph{}, err
+	}
+	MaxN := 0.5 + math.Sqrt(2*float64(maxInt)+0.25)
+	if float64(n) > MaxN {
+		return &DenseGraph{}, errors.New("Graph too large")
 	}
 
 	if i+int(((n*(n-1))/2)+5)/6 > len(s) {
@@ -121,6 +108,7 @@ func Graph6Encode(g Graph) string {
 
 //Sparse6Decode decode returns the graph with Sparse6 encoding s or an error if no such graph exists.
 //For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+//Incremental Sparse6 encodings (starting with ;) depend on the previous graph and should be decoded using IncrementalSparse6Decode.
 func Sparse6Decode(s string) (*SparseGraph, error) {
 	//Strip the header if present
 	if strings.HasPrefix(s, ">>sparse6<<") {
@@ -128,232 +116,242 @@ func Sparse6Decode(s string) (*SparseGraph, error) {
 	}
 
 	//Check the initial byte and remove it.
-	if s[0] != 58 {
-		return &SparseGraph{}, fmt.Errorf("Incorrect first character. Expected: : Found: %v", s[0])
+	if len(s) == 0 || s[0] != 58 {
+		return &SparseGraph{}, errors.New("Incorrect first character. Expected: :")
 	}
-	s = s[1:]
 
+	n, edges, err := decodeSparse6Edges(s[1:])
+	if err != nil {
+		return &SparseGraph{}, err
+	}
+	g := NewSparse(n, nil)
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1])
+	}
+	return g, nil
+}
+
+//Sparse6Encode returns an encoding of g. Note that the encoding is not unique but this should align with the format used by showg, geng, nauty etc.
+//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+func Sparse6Encode(g Graph) string {
+	n := g.N()
+	edges := sparse6Edges(g)
+	s := make([]byte, 1, 10+((bits.Len(uint(n))+1)*2*len(edges)+5)/6)
+	s[0] = 58
+	return string(appendSparse6Edges(s, n, edges))
+}
+
+//IncrementalSparse6Decode returns the graph with the incremental Sparse6 encoding s where prev is the previous graph in the stream.
+//An incremental Sparse6 encoding starts with ; and lists the edges whose presence should be toggled in prev so the graph has the same number of vertices as prev. An error is returned if the numbers of vertices differ.
+//For convenience, s may also be a normal Sparse6 encoding in which case prev is ignored.
+//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+func IncrementalSparse6Decode(prev Graph, s string) (*SparseGraph, error) {
+	if strings.HasPrefix(s, ">>sparse6<<") {
+		s = s[11:]
+	}
+	if len(s) > 0 && s[0] == 58 {
+		return Sparse6Decode(s)
+	}
+	if len(s) == 0 || s[0] != 59 {
+		return &SparseGraph{}, errors.New("Incorrect first character. Expected: ;")
+	}
+
+	n, edges, err := decodeSparse6Edges(s[1:])
+	if err != nil {
+		return &SparseGraph{}, err
+	}
+	if prev == nil {
+		return &SparseGraph{}, errors.New("No previous graph for an incremental encoding")
+	}
+	if prev.N() != n {
+		return &SparseGraph{}, fmt.Errorf("Number of vertices differs from the previous graph. Found: %v Expected: %v", n, prev.N())
+	}
+
+	neighbourhoods := make([]sortints.SortedInts, n)
+	for i := range neighbourhoods {
+		neighbourhoods[i] = prev.Neighbours(i)
+	}
+	g := NewSparse(n, neighbourhoods)
+	for _, e := range edges {
+		if g.IsEdge(e[0], e[1]) {
+			g.RemoveEdge(e[0], e[1])
+		} else {
+			g.AddEdge(e[0], e[1])
+		}
+	}
+	return g, nil
+}
+
+//IncrementalSparse6Encode returns the incremental Sparse6 encoding of g relative to the previous graph prev. The encoding lists the edges which are in exactly one of prev and g.
+//If prev is nil, has a different number of vertices or the normal Sparse6 encoding is no longer, the normal Sparse6 encoding is returned instead.
+//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+func IncrementalSparse6Encode(prev, g Graph) string {
+	edges := sparse6Edges(g)
+	if prev == nil || prev.N() != g.N() {
+		s := make([]byte, 1, 10+((bits.Len(uint(g.N()))+1)*2*len(edges)+5)/6)
+		s[0] = 58
+		return string(appendSparse6Edges(s, g.N(), edges))
+	}
+	return incrementalSparse6Encode(g.N(), sparse6Edges(prev), edges)
+}
+
+//incrementalSparse6Encode returns the shorter of the incremental Sparse6 encoding and the normal Sparse6 encoding of the graph on n vertices with the given edges. The previous graph has edges prevEdges.
+//The edges must be sorted as in sparse6Edges.
+func incrementalSparse6Encode(n int, prevEdges, edges [][2]int) string {
+	//Find the symmetric difference by merging the sorted lists.
+	diff := make([][2]int, 0)
+	i, j := 0, 0
+	for i < len(prevEdges) || j < len(edges) {
+		switch {
+		case j == len(edges) || (i < len(prevEdges) && edgeLess(prevEdges[i], edges[j])):
+			diff = append(diff, prevEdges[i])
+			i++
+		case i == len(prevEdges) || edgeLess(edges[j], prevEdges[i]):
+			diff = append(diff, edges[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+
+	//Each edge costs roughly the same so encode whichever has fewer edges.
+	s := make([]byte, 1, 10+((bits.Len(uint(n))+1)*2*len(edges)+5)/6)
+	if len(diff) < len(edges) {
+		s[0] = 59
+		return string(appendSparse6Edges(s, n, diff))
+	}
+	s[0] = 58
+	return string(appendSparse6Edges(s, n, edges))
+}
+
+//sparse6Edges returns the edges of g as pairs {u, v} with u < v. The edges are sorted by v and then by u which is the order they appear in a Sparse6 encoding.
+func sparse6Edges(g Graph) [][2]int {
+	edges := make([][2]int, 0, g.M())
+	for v := 1; v < g.N(); v++ {
+		for _, u := range g.Neighbours(v) {
+			if u > v {
+				break
+			}
+			edges = append(edges, [2]int{u, v})
+		}
+	}
+	return edges
+}
+
+//edgeLess returns true if the edge a comes before the edge b in the order used by sparse6Edges.
+func edgeLess(a, b [2]int) bool {
+	return a[1] < b[1] || (a[1] == b[1] && a[0] < b[0])
+}
+
+//decodeSparse6Edges decodes the part of a Sparse6 encoding after the initial : or ; and returns the number of vertices and the edges as pairs {u, v} with u <= v.
+//The edges are in the order they are encoded and may contain loops and repeated edges.
+func decodeSparse6Edges(s string) (int, [][2]int, error) {
 	//Check every byte is in the correct range.
 	for i := 0; i < len(s); i++ {
 		if s[i] < 63 || s[i] > 126 {
-			return &SparseGraph{}, fmt.Errorf("Byte out of range (63-126). Index: %v Value: %v", i, s[i])
+			return 0, nil, fmt.Errorf("Byte out of range (63-126). Index: %v Value: %v", i, s[i])
 		}
 	}
 
-	var n uint64
-	i := 0
+	n, i, err := decodeSize(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if n > uint64(maxInt) {
+		return 0, nil, errors.New("Graph too large")
+	}
 
-	if s[0] != 126 {
-		n = uint64(s[0] - 63)
-		i = 1
-	} else if s[1] != 126 {
-		if len(s) < 4 {
-			return &SparseGraph{}, errors.New("String too short - unable to decode n")
-		}
-		n = (uint64(s[1]-63) << 12) + (uint64(s[2]-63) << 6) + uint64(s[3]-63)
-		i = 4
-	} else {
-		if len(s) < 8 {
-			return &SparseGraph{}, errors.New("String too short - unable to decode n")
-		}
-		n = (uint64(s[2]-63) << 30) + (uint64(s[3]-63) << 24) + (uint64(s[4]-63) << 18) + (uint64(s[5]-63) << 12) + (uint64(s[6]-63) << 6) + uint64(s[7]-63)
-		i = 8
+	edges := make([][2]int, 0)
+	if n <= 1 {
+		return int(n), edges, nil
 	}
 
-	g := NewSparse(int(n), nil)
 	v := 0
-	k := 64 - bits.LeadingZeros64(n-1)
-	var bitIndex uint
-	for {
-		b := ((s[i] - 63) >> (5 - bitIndex)) & 1
+	k := bits.Len64(n - 1)
+	//Read the bits one at a time and stop when there are fewer than k + 1 bits left.
+	bitIndex := 6 * i
+	totalBits := 6 * len(s)
+	nextBit := func() int {
+		b := int((s[bitIndex/6]-63)>>uint(5-bitIndex%6)) & 1
 		bitIndex++
-		if bitIndex == 6 {
-			bitIndex = 0
-			i++
-			if i >= len(s) {
-				return g, nil
-			}
-		}
-		if b == 1 {
+		return b
+	}
+	for totalBits-bitIndex >= k+1 {
+		if nextBit() == 1 {
 			v++
 		}
 		x := 0
 		for j := 0; j < k; j++ {
-			if ((s[i]-63)>>(5-bitIndex))&1 == 1 {
-				x |= 1 << uint(k-j-1)
-			}
-			bitIndex++
-			if bitIndex == 6 {
-				bitIndex = 0
-				i++
-				if i >= len(s) {
-					return g, nil
-				}
-			}
+			x = (x << 1) | nextBit()
 		}
 		if x > v {
 			v = x
-		} else {
-			g.AddEdge(v, x)
+		} else if v < int(n) {
+			edges = append(edges, [2]int{x, v})
 		}
 	}
+	return int(n), edges, nil
 }
 
-//Sparse6Encode returns an encoding of g. Note that the encoding is not unique but this should align with the format used by showg, geng, nauty etc.
-//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
-func Sparse6Encode(g Graph) string {
-	n := g.N()
-	m := g.M()
-	//Number of bits needed to express n -1.
-	k := 64 - bits.LeadingZeros64(uint64(n-1))
-
-	var s []byte
-
-	//To encode an edge we may possibly need to a 1 + k bits to move to the vertex and then another 1 + k bits to encode the edge.
-
+//appendSparse6Edges appends the part of a Sparse6 encoding after the initial : or ; for the graph on n vertices with the given edges. The edges must be sorted as in sparse6Edges.
+func appendSparse6Edges(s []byte, n int, edges [][2]int) []byte {
+	s = appendSize(s, n)
 	if n <= 1 {
-		return string([]byte{58, byte(n + 63)})
-	} else if n <= 62 {
-		s = make([]byte, 2, 2+((k+1)*2*m+5)/6)
-		s[0] = 58
-		s[1] = byte(n + 63)
-	} else if n <= 258047 {
-		s = make([]byte, 5, 5+((k+1)*2*m+5)/6)
-		s[0] = 58
-		s[1] = 126
-		s[2] = byte((n>>12)&63) + 63
-		s[3] = byte((n>>6)&63) + 63
-		s[4] = byte(n&63) + 63
-	} else if n <= 68719476735 {
-		s = make([]byte, 9, 9+((k+1)*2*m+5)/6)
-		s[0] = 58
-		s[1] = 126
-		s[2] = 126
-		s[3] = byte((n>>30)&63) + 63
-		s[4] = byte((n>>24)&63) + 63
-		s[5] = byte((n>>18)&63) + 63
-		s[6] = byte((n>>12)&63) + 63
-		s[7] = byte((n>>6)&63) + 63
-		s[8] = byte(n&63) + 63
-	} else {
-		panic("Graph too large")
+		return s
 	}
 
-	var b byte
+	//Number of bits needed to express n - 1.
+	k := bits.Len(uint(n - 1))
 
-	v := 0
+	var b byte
 	currentBitPosition := 0
-	for i := 1; i < n; i++ {
-		neighbours := g.Neighbours(i)
-		for _, u := range neighbours {
-			if u > i {
-				break
-			}
-
-			if i == v {
-				//b[-] == 0
-				currentBitPosition++
-				if currentBitPosition == 6 {
-					s = append(s, b+63)
-					b = 0
-					currentBitPosition = 0
-				}
-				//x[-] = u
-				for j := 0; j < k; j++ {
-					if (u>>uint(k-j-1))&1 == 1 {
-						b |= 1 << uint(5-currentBitPosition)
-					}
-					currentBitPosition++
-					if currentBitPosition == 6 {
-						s = append(s, b+63)
-						b = 0
-						currentBitPosition = 0
-					}
-				}
-			} else if i == v+1 {
-				v++
-				//b[-] == 1
-				b |= 1 << uint(5-currentBitPosition)
-				currentBitPosition++
-				if currentBitPosition == 6 {
-					s = append(s, b+63)
-					b = 0
-					currentBitPosition = 0
-				}
-				//x[-] = u
-				for j := 0; j < k; j++ {
-					if (u>>uint(k-j-1))&1 == 1 {
-						b |= 1 << uint(5-currentBitPosition)
-					}
-					currentBitPosition++
-					if currentBitPosition == 6 {
-						s = append(s, b+63)
-						b = 0
-						currentBitPosition = 0
-					}
-				}
-			} else {
-				v = i
-				//First we move.
-
-				//I believe this is arbitrarily 0 or 1 but I think 1 is used by default.
-				//b[-] == 1
-				b |= 1 << uint(5-currentBitPosition)
-				currentBitPosition++
-				if currentBitPosition == 6 {
-					s = append(s, b+63)
-					b = 0
-					currentBitPosition = 0
-				}
-				//x[-] = i
-				for j := 0; j < k; j++ {
-					if (i>>uint(k-j-1))&1 == 1 {
-						b |= 1 << uint(5-currentBitPosition)
-					}
-					currentBitPosition++
-					if currentBitPosition == 6 {
-						s = append(s, b+63)
-						b = 0
-						currentBitPosition = 0
-					}
-				}
-
-				//Now add the edge.
+	appendBit := func(bit int) {
+		if bit == 1 {
+			b |= 1 << uint(5-currentBitPosition)
+		}
+		currentBitPosition++
+		if currentBitPosition == 6 {
+			s = append(s, b+63)
+			b = 0
+			currentBitPosition = 0
+		}
+	}
+	appendX := func(x int) {
+		for j := k - 1; j >= 0; j-- {
+			appendBit((x >> uint(j)) & 1)
+		}
+	}
 
-				//b[-] == 0
-				currentBitPosition++
-				if currentBitPosition == 6 {
-					s = append(s, b+63)
-					b = 0
-					currentBitPosition = 0
-				}
-				//x[-] = u
-				for j := 0; j < k; j++ {
-					if (u>>uint(k-j-1))&1 == 1 {
-						b |= 1 << uint(5-currentBitPosition)
-					}
-					currentBitPosition++
-					if currentBitPosition == 6 {
-						s = append(s, b+63)
-						b = 0
-						currentBitPosition = 0
-					}
-				}
-			}
+	v := 0
+	for _, e := range edges {
+		u, i := e[0], e[1]
+		if i == v {
+			appendBit(0)
+			appendX(u)
+		} else if i == v+1 {
+			v++
+			appendBit(1)
+			appendX(u)
+		} else {
+			//First we move. I believe this is arbitrarily 0 or 1 but I think 1 is used by default.
+			v = i
+			appendBit(1)
+			appendX(i)
+			//Now add the edge.
+			appendBit(0)
+			appendX(u)
 		}
 	}
 
 	if currentBitPosition == 0 {
-		return string(s)
+		return s
 	}
 
 	//Padding
-
-	if (n == 2 || n == 4 || n == 8 || n == 16) && 6-currentBitPosition > k+1 {
-		degrees := g.Degrees()
-		if degrees[n-2] > 0 && degrees[n-1] == 0 {
-			currentBitPosition++
-		}
+	//If the padding could be read as moving to the vertex n - 1 and adding an edge, we need to start the padding with a 0.
+	if (n == 2 || n == 4 || n == 8 || n == 16) && 6-currentBitPosition > k+1 && v == n-2 {
+		currentBitPosition++
 	}
 
 	//Pad with 1s.
@@ -361,9 +359,7 @@ func Sparse6Encode(g Graph) string {
 		b += 1 << uint(5-j)
 	}
 
-	s = append(s, b+63)
-
-	return string(s)
+	return append(s, b+63)
 }
 
 //MulticodeEncode returns the Multicode encoding of g.
@@ -542,3 +538,117 @@ func AdjacencyMatrixEncode(g Graph) string {
 	sb.WriteString("]")
 	return sb.String()
 }
+
+//Digraph6Decode returns the digraph with Digraph6 encoding s or an error.
+//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+//The format allows loops but a DenseDigraph can't contain loops so an error is returned if the encoding contains a loop.
+func Digraph6Decode(s string) (*DenseDigraph, error) {
+	if strings.HasPrefix(s, ">>digraph6<<") {
+		s = s[12:]
+	}
+
+	//Check the initial byte and remove it.
+	if len(s) == 0 || s[0] != '&' {
+		return &DenseDigraph{}, errors.New("Incorrect first character. Expected: &")
+	}
+	s = s[1:]
+
+	//Check the bytes are in 63-126 (inclusive).
+	for i := 0; i < len(s); i++ {
+		if s[i] < 63 || s[i] > 126 {
+			return &DenseDigraph{}, fmt.Errorf("Byte out of range. Index: %v Value: %v", i, s[i])
+		}
+	}
+
+	n, i, err := decodeSize(s)
+	if err != nil {
+		return &DenseDigraph{}, err
+	}
+	if float64(n) > math.Sqrt(float64(maxInt)) {
+		return &DenseDigraph{}, errors.New("Graph too large")
+	}
+
+	if uint64(len(s)-i) < (n*n+5)/6 {
+		return &DenseDigraph{}, errors.New("String too short - unable to decode edges")
+	}
+
+	g := NewDenseDigraph(int(n), nil)
+	index := 0
+	for u := 0; u < int(n); u++ {
+		for v := 0; v < int(n); v++ {
+			if (s[i+index/6]-63)&(1<<uint(5-(index%6))) != 0 {
+				if u == v {
+					return &DenseDigraph{}, fmt.Errorf("Loops are not supported. Vertex: %v", u)
+				}
+				g.AddEdge(u, v)
+			}
+			index++
+		}
+	}
+	return g, nil
+}
+
+//Digraph6Encode returns the Digraph6 encoding of g.
+//For the definition of the format see: https://users.cecs.anu.edu.au/~bdm/data/formats.txt
+func Digraph6Encode(g Digraph) string {
+	n := g.N()
+	s := make([]byte, 1, 9+(n*n+5)/6)
+	s[0] = '&'
+	s = appendSize(s, n)
+
+	var b byte
+	bIndex := 0
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			if u != v && g.IsEdge(u, v) {
+				b += 1 << uint(5-bIndex)
+			}
+			bIndex++
+			if bIndex == 6 {
+				s = append(s, b+63)
+				bIndex = 0
+				b = 0
+			}
+		}
+	}
+
+	if bIndex != 0 {
+		s = append(s, b+63)
+	}
+
+	return string(s)
+}
+
+//appendSize appends the encoding N(n) of the number of vertices which is shared by the Graph6, Sparse6 and Digraph6 formats.
+func appendSize(s []byte, n int) []byte {
+	if n <= 62 {
+		return append(s, byte(n+63))
+	} else if n <= 258047 {
+		return append(s, 126, byte((n>>12)&63)+63, byte((n>>6)&63)+63, byte(n&63)+63)
+	} else if n <= 68719476735 {
+		return append(s, 126, 126, byte((n>>30)&63)+63, byte((n>>24)&63)+63, byte((n>>18)&63)+63, byte((n>>12)&63)+63, byte((n>>6)&63)+63, byte(n&63)+63)
+	}
+	panic("Graph too large")
+}
+
+//decodeSize decodes the number of vertices N(n) at the start of s and returns the number of vertices and the number of bytes used.
+//It assumes the bytes of s have already been checked to be in the range 63-126.
+func decodeSize(s string) (n uint64, i int, err error) {
+	if len(s) == 0 {
+		return 0, 0, errors.New("String too short - unable to decode n")
+	}
+	if s[0] != 126 {
+		return uint64(s[0] - 63), 1, nil
+	}
+	if len(s) < 4 {
+		return 0, 0, errors.New("String too short - unable to decode n")
+	}
+	if s[1] != 126 {
+		return (uint64(s[1]-63) << 12) + (uint64(s[2]-63) << 6) + uint64(s[3]-63), 4, nil
+	}
+	if len(s) < 8 {
+		return 0, 0, errors.New("String too short - unable to decode n")
+	}
+	n = (uint64(s[2]-63) << 30) + (uint64(s[3]-63) << 24) + (uint64(s[4]-63) << 18) + (uint64(s[5]-63) << 12) + (uint64(s[6]-63) << 6) + uint64(s[7]-63)
+	return n, 8, nil
+}
@@ -110,3 +110,52 @@ func TestPruferDecode(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestDigraph6(t *testing.T) {
+	//The example from formats.txt.
+	g := graph.NewDenseDigraph(5, nil)
+	g.AddEdge(0, 2)
+	g.AddEdge(0, 4)
+	g.AddEdge(3, 1)
+	g.AddEdge(3, 4)
+	d6 := "&DI?AO?"
+
+	if foundEncode := graph.Digraph6Encode(g); foundEncode != d6 {
+		t.Errorf("Expected: %v Found: %v", d6, foundEncode)
+	}
+
+	for _, s := range []string{d6, ">>digraph6<<" + d6} {
+		foundG, err := graph.Digraph6Decode(s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !digraphsEqual(g, foundG) {
+			t.Errorf("Decoding %v gave the wrong digraph", s)
+		}
+	}
+
+	//A loop at vertex 0 can't be decoded.
+	if _, err := graph.Digraph6Decode("&B_?"); err == nil {
+		t.Error("Expected an error when decoding a loop")
+	}
+
+	for _, s := range []string{"", "&", "DQc", "&D?"} {
+		if _, err := graph.Digraph6Decode(s); err == nil {
+			t.Errorf("Expected an error when decoding %q", s)
+		}
+	}
+
+	for _, n := range []int{0, 1, 2, 7, 30, 63, 100} {
+		g := randomDigraph(n, 0.4, int64(n))
+		s := graph.Digraph6Encode(g)
+		h, err := graph.Digraph6Decode(s)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if !digraphsEqual(g, h) {
+			t.Errorf("Round trip failed for n = %v", n)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+package graph
+
+//IsPlanarDMP exports isPlanarDMP so the tests can cross-check IsPlanar against it.
+var IsPlanarDMP = isPlanarDMP
@@ -0,0 +1,136 @@
+package graph
+
+//This file contains functions for working with the faces of a graph embedded in the plane. The embedding is given as a rotation system as returned by PlanarEmbedding: rotation[v] lists the neighbours of v in clockwise order.
+//The half-edge from v to rotation[v][i] is labelled offset[v] + i and the faces are found by moving from the half-edge (u, v) to the half-edge (v, w) where w is the neighbour before u in rotation[v].
+
+//halfEdges labels the half-edges of the rotation system and returns the offsets, the start of each half-edge and the label of the reverse of each half-edge.
+func halfEdges(rotation [][]int) (offset, from, reverse []int) {
+	n := len(rotation)
+	offset = make([]int, n+1)
+	for v := 0; v < n; v++ {
+		offset[v+1] = offset[v] + len(rotation[v])
+	}
+	from = make([]int, offset[n])
+	//incoming[u] lists the half-edges ending at u in increasing order of their start. outgoing[u] lists the half-edges starting at u in increasing order of their end. The two lists match up the reverse half-edges.
+	incoming := make([][]int, n)
+	for v := 0; v < n; v++ {
+		for i, u := range rotation[v] {
+			from[offset[v]+i] = v
+			incoming[u] = append(incoming[u], offset[v]+i)
+		}
+	}
+	outgoing := make([][]int, n)
+	for u := 0; u < n; u++ {
+		for _, h := range incoming[u] {
+			outgoing[from[h]] = append(outgoing[from[h]], h)
+		}
+	}
+	reverse = make([]int, offset[n])
+	for u := 0; u < n; u++ {
+		if len(incoming[u]) != len(outgoing[u]) {
+			panic("The rotation system is not symmetric.")
+		}
+		for k, h := range incoming[u] {
+			r := outgoing[u][k]
+			if rotation[u][r-offset[u]] != from[h] {
+				panic("The rotation system is not symmetric.")
+			}
+			reverse[h] = r
+		}
+	}
+	return offset, from, reverse
+}
+
+//faceWalks returns the boundary walks of the faces and the face containing each half-edge.
+func faceWalks(rotation [][]int) (faces [][]int, faceOf []int, offset, reverse []int) {
+	offset, from, reverse := halfEdges(rotation)
+	faceOf = make([]int, len(reverse))
+	for h := range faceOf {
+		faceOf[h] = -1
+	}
+	faces = make([][]int, 0)
+	for v := range rotation {
+		for i := range rotation[v] {
+			h := offset[v] + i
+			if faceOf[h] != -1 {
+				continue
+			}
+			f := len(faces)
+			walk := make([]int, 0)
+			start := v
+			for faceOf[h] == -1 {
+				faceOf[h] = f
+				walk = append(walk, start)
+				r := reverse[h]
+				start = from[r]
+				d := len(rotation[start])
+				j := r - offset[start]
+				h = offset[start] + (j+d-1)%d
+			}
+			faces = append(faces, walk)
+		}
+	}
+	return faces, faceOf, offset, reverse
+}
+
+//Faces returns the faces of the embedding given by the rotation system. Each face is given by the vertices in order around its boundary walk so a vertex may appear more than once e.g. the tree with k edges has a single face whose walk has length 2k.
+//The faces of each component are found separately and isolated vertices don't have any faces so, if the graph is connected and has at least one edge, Euler's formula says that n - m + len(faces) = 2 for a planar embedding.
+//This panics if the rotation system isn't symmetric i.e. if u appears in rotation[v] a different number of times to v in rotation[u].
+func Faces(rotation [][]int) [][]int {
+	faces, _, _, _ := faceWalks(rotation)
+	return faces
+}
+
+//FaceSizes returns the size of each face of the embedding given by the rotation system in the same order as Faces. The size of a face is the length of its boundary walk so a bridge contributes 2 to the size of its face.
+func FaceSizes(rotation [][]int) []int {
+	faces := Faces(rotation)
+	sizes := make([]int, len(faces))
+	for i, f := range faces {
+		sizes[i] = len(f)
+	}
+	return sizes
+}
+
+//PlanarDual returns the dual of the connected plane graph given by the rotation system. The vertex i of the dual is the face Faces(rotation)[i] and there is an edge of the dual for every edge of the graph joining the faces on either side. Bridges give loops and two faces which share more than one edge are joined by multiple edges.
+//The edges of the dual are in the order of the edges uv with u < v listed in increasing order of u and then in the order of rotation[u].
+//The graph with a single vertex has a single face and this panics if the graph is not connected.
+func PlanarDual(rotation [][]int) *Multigraph {
+	n := len(rotation)
+	faces, faceOf, offset, reverse := faceWalks(rotation)
+	if n == 1 {
+		return NewMultigraph(1, nil)
+	}
+	//Check the graph is connected.
+	seen := make([]bool, n)
+	toCheck := make([]int, 0, n)
+	if n > 0 {
+		seen[0] = true
+		toCheck = append(toCheck, 0)
+	}
+	count := len(toCheck)
+	for len(toCheck) > 0 {
+		v := toCheck[len(toCheck)-1]
+		toCheck = toCheck[:len(toCheck)-1]
+		for _, u := range rotation[v] {
+			if !seen[u] {
+				seen[u] = true
+				count++
+				toCheck = append(toCheck, u)
+			}
+		}
+	}
+	if count != n {
+		panic("The graph is not connected.")
+	}
+
+	ends := make([][2]int, 0, len(reverse)/2)
+	for v := range rotation {
+		for i, u := range rotation[v] {
+			if u > v {
+				h := offset[v] + i
+				ends = append(ends, [2]int{faceOf[h], faceOf[reverse[h]]})
+			}
+		}
+	}
+	return &Multigraph{NumberOfVertices: len(faces), Ends: ends}
+}
@@ -0,0 +1,133 @@
+package graph_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+func TestFaces(t *testing.T) {
+	tests := []struct {
+		g     graph.Graph
+		sizes []int
+	}{
+		{graph.CompleteGraph(4), []int{3, 3, 3, 3}},
+		{graph.HypercubeGraph(3), []int{4, 4, 4, 4, 4, 4}},
+		{graph.Cycle(7), []int{7, 7}},
+		{graph.Path(5), []int{8}},
+		{graph.Star(6), []int{10}},
+		{graph.CompletePartiteGraph(2, 5), []int{4, 4, 4, 4, 4}},
+		{graph.NewDense(1, nil), []int{}},
+	}
+	for _, test := range tests {
+		ok, rotation, _ := graph.PlanarEmbedding(test.g)
+		if !ok {
+			t.Fatalf("%v should be planar", graph.Graph6Encode(test.g))
+		}
+		sizes := graph.FaceSizes(rotation)
+		sort.Ints(sizes)
+		if !ints.Equal(sizes, test.sizes) {
+			t.Errorf("Wrong face sizes for %v. Found: %v Expected: %v", graph.Graph6Encode(test.g), sizes, test.sizes)
+		}
+	}
+
+	//Check Euler's formula and that every edge is used twice on random connected planar graphs.
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		g := randomPlanarGraph(2+r.Intn(30), r)
+		ok, rotation, _ := graph.PlanarEmbedding(g)
+		if !ok {
+			t.Fatalf("%v should be planar", graph.Graph6Encode(g))
+		}
+		faces := graph.Faces(rotation)
+		if g.N()-g.M()+len(faces) != 2 {
+			t.Fatalf("Euler's formula fails for %v with faces %v", graph.Graph6Encode(g), faces)
+		}
+		used := make(map[[2]int]int)
+		for _, f := range faces {
+			for i, v := range f {
+				u := f[(i+1)%len(f)]
+				if !g.IsEdge(u, v) {
+					t.Fatalf("The face %v of %v isn't a walk", f, graph.Graph6Encode(g))
+				}
+				used[[2]int{v, u}]++
+			}
+		}
+		if len(used) != 2*g.M() {
+			t.Fatalf("The faces of %v don't use every half-edge exactly once", graph.Graph6Encode(g))
+		}
+		for _, count := range used {
+			if count != 1 {
+				t.Fatalf("The faces of %v don't use every half-edge exactly once", graph.Graph6Encode(g))
+			}
+		}
+	}
+}
+
+func TestPlanarDual(t *testing.T) {
+	//The dual of the cube is the octahedron.
+	_, rotation, _ := graph.PlanarEmbedding(graph.HypercubeGraph(3))
+	dual := graph.PlanarDual(rotation)
+	if !isomorphic(dual.Underlying(), graph.CompletePartiteGraph(2, 2, 2)) || dual.M() != 12 {
+		t.Errorf("The dual of the cube is not the octahedron")
+	}
+
+	//K4 is self-dual.
+	_, rotation, _ = graph.PlanarEmbedding(graph.CompleteGraph(4))
+	dual = graph.PlanarDual(rotation)
+	if !isomorphic(dual.Underlying(), graph.CompleteGraph(4)) || dual.M() != 6 {
+		t.Errorf("The dual of K4 is not K4")
+	}
+
+	//The dual of a tree with k edges is a single vertex with k loops.
+	_, rotation, _ = graph.PlanarEmbedding(graph.Star(5))
+	dual = graph.PlanarDual(rotation)
+	if dual.N() != 1 || dual.M() != 4 || dual.Multiplicity(0, 0) != 4 || !ints.Equal(dual.Degrees(), []int{8}) {
+		t.Errorf("The dual of the star is not a single vertex with 4 loops")
+	}
+
+	//The dual of a cycle has two vertices joined by a multiple edge.
+	_, rotation, _ = graph.PlanarEmbedding(graph.Cycle(5))
+	dual = graph.PlanarDual(rotation)
+	if dual.N() != 2 || dual.Multiplicity(0, 1) != 5 || !ints.Equal(dual.Neighbours(0), []int{1, 1, 1, 1, 1}) {
+		t.Errorf("The dual of C5 is not two vertices joined by 5 edges")
+	}
+
+	//The degrees of the dual are the face sizes.
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		g := randomPlanarGraph(2+r.Intn(30), r)
+		_, rotation, _ := graph.PlanarEmbedding(g)
+		dual := graph.PlanarDual(rotation)
+		if !ints.Equal(dual.Degrees(), graph.FaceSizes(rotation)) || dual.M() != g.M() {
+			t.Fatalf("The dual of %v has the wrong degrees", graph.Graph6Encode(g))
+		}
+	}
+}
+
+//randomPlanarGraph returns a random connected planar graph on n vertices by starting with a random tree and adding edges at random which keep the graph planar.
+func randomPlanarGraph(n int, r *rand.Rand) *graph.SparseGraph {
+	g := graph.NewSparse(n, nil)
+	for v := 1; v < n; v++ {
+		g.AddEdge(v, r.Intn(v))
+	}
+	for i := 0; i < 2*n; i++ {
+		u, v := r.Intn(n), r.Intn(n)
+		if u == v || g.IsEdge(u, v) {
+			continue
+		}
+		g.AddEdge(u, v)
+		if !graph.IsPlanar(g) {
+			g.RemoveEdge(u, v)
+		}
+	}
+	return g
+}
+
+//isomorphic returns true if g and h are isomorphic by comparing their canonical forms.
+func isomorphic(g, h graph.EditableGraph) bool {
+	return graph.Graph6Encode(g.InducedSubgraph(graph.CanonicalIsomorph(g))) == graph.Graph6Encode(h.InducedSubgraph(graph.CanonicalIsomorph(h)))
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"math/big"
+
+	"github.com/Tom-Johnston/mamba/lp"
+)
+
+//FractionalChromaticNumber returns the fractional chromatic number χ_f of g and a fractional colouring which achieves it. A fractional colouring gives each independent set a non-negative weight so that the total weight of the sets containing each vertex is at least 1 and χ_f is the minimum total weight of a fractional colouring.
+//The sets in the colouring are maximal independent sets and weights[i] is the weight of sets[i]. Only the sets with a positive weight are returned.
+//The linear program over the maximal independent sets (found using AllMaximalCliques on the complement of g) is solved by column generation. The linear program is solved exactly using only some of the sets and the solution of the dual is used to find a set which would improve the solution. This is added and the process repeats until there are no such sets.
+func FractionalChromaticNumber(g Graph) (chromaticNumber *big.Rat, sets [][]int, weights []*big.Rat) {
+	chromaticNumber, sets, weights, _ = fractionalColouring(g)
+	return chromaticNumber, sets, weights
+}
+
+//FractionalCliqueNumber returns the fractional clique number of g and a fractional clique which achieves it. A fractional clique gives each vertex a non-negative weight so that the total weight of each independent set is at most 1 and the fractional clique number is the maximum total weight of a fractional clique.
+//The linear program is the dual of the one for the fractional chromatic number so the fractional clique number is equal to the fractional chromatic number. See FractionalChromaticNumber for the method.
+func FractionalCliqueNumber(g Graph) (cliqueNumber *big.Rat, weights []*big.Rat) {
+	cliqueNumber, _, _, weights = fractionalColouring(g)
+	return cliqueNumber, weights
+}
+
+//fractionalColouring returns the fractional chromatic number, an optimal fractional colouring and an optimal fractional clique of g.
+func fractionalColouring(g Graph) (value *big.Rat, sets [][]int, setWeights []*big.Rat, vertexWeights []*big.Rat) {
+	n := g.N()
+	if n == 0 {
+		return new(big.Rat), [][]int{}, []*big.Rat{}, []*big.Rat{}
+	}
+	c := make(chan []int)
+	go AllMaximalCliques(Complement(g), c)
+	independentSets := make([][]int, 0)
+	for set := range c {
+		independentSets = append(independentSets, set)
+	}
+
+	//Start with enough sets to cover every vertex.
+	used := make([]bool, len(independentSets))
+	columns := make([]int, 0)
+	covered := make([]bool, n)
+	for i, set := range independentSets {
+		for _, v := range set {
+			if !covered[v] {
+				used[i] = true
+				columns = append(columns, i)
+				for _, u := range set {
+					covered[u] = true
+				}
+				break
+			}
+		}
+	}
+
+	//Minimise the total weight subject to each vertex having weight at least 1.
+	one := big.NewRat(1, 1)
+	ones := make([]*big.Rat, n)
+	for v := range ones {
+		ones[v] = one
+	}
+	problem := lp.NewMinimisation(ones)
+	column := make([]*big.Rat, n)
+	addColumn := func(i int) {
+		for v := range column {
+			column[v] = nil
+		}
+		for _, v := range independentSets[i] {
+			column[v] = one
+		}
+		problem.AddVariable(one, column)
+	}
+	for _, i := range columns {
+		addColumn(i)
+	}
+	weight := new(big.Int)
+	best := new(big.Int)
+	numerators := make([]*big.Int, n)
+	for v := range numerators {
+		numerators[v] = new(big.Int)
+	}
+	denominator := new(big.Int)
+	gcd := new(big.Int)
+	for {
+		solution := problem.Solve()
+
+		//Add the set with the largest total dual weight if this is more than 1. The weights are written with a common denominator to avoid reducing fractions when adding them.
+		denominator.SetInt64(1)
+		for _, y := range solution.Y {
+			gcd.GCD(nil, nil, denominator, y.Denom())
+			denominator.Mul(denominator, y.Denom())
+			denominator.Quo(denominator, gcd)
+		}
+		for v, y := range solution.Y {
+			numerators[v].Quo(denominator, y.Denom())
+			numerators[v].Mul(numerators[v], y.Num())
+		}
+		next := -1
+		best.Set(denominator)
+		for i, set := range independentSets {
+			if used[i] {
+				continue
+			}
+			weight.SetInt64(0)
+			for _, v := range set {
+				weight.Add(weight, numerators[v])
+			}
+			if weight.Cmp(best) > 0 {
+				next = i
+				best.Set(weight)
+			}
+		}
+		if next == -1 {
+			for j, i := range columns {
+				if solution.X[j].Sign() > 0 {
+					sets = append(sets, independentSets[i])
+					setWeights = append(setWeights, solution.X[j])
+				}
+			}
+			return solution.Value, sets, setWeights, solution.Y
+		}
+		used[next] = true
+		columns = append(columns, next)
+		addColumn(next)
+	}
+}
@@ -0,0 +1,106 @@
+package graph_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//checkFractional checks that the fractional colouring and the fractional clique are valid and have total weight equal to value.
+func checkFractional(t *testing.T, g graph.Graph, value *big.Rat, sets [][]int, setWeights []*big.Rat, vertexWeights []*big.Rat) {
+	t.Helper()
+	total := new(big.Rat)
+	covered := make([]*big.Rat, g.N())
+	for v := range covered {
+		covered[v] = new(big.Rat)
+	}
+	for i, set := range sets {
+		if setWeights[i].Sign() <= 0 {
+			t.Fatalf("Found a non-positive weight %v", setWeights[i])
+		}
+		total.Add(total, setWeights[i])
+		for j, v := range set {
+			covered[v].Add(covered[v], setWeights[i])
+			for _, u := range set[:j] {
+				if g.IsEdge(u, v) {
+					t.Fatalf("The set %v isn't independent in %v", set, graph.Graph6Encode(g))
+				}
+			}
+		}
+	}
+	if total.Cmp(value) != 0 {
+		t.Fatalf("The fractional colouring of %v has weight %v Expected: %v", graph.Graph6Encode(g), total, value)
+	}
+	for v := range covered {
+		if covered[v].Cmp(big.NewRat(1, 1)) < 0 {
+			t.Fatalf("The vertex %v of %v has weight %v in the fractional colouring", v, graph.Graph6Encode(g), covered[v])
+		}
+	}
+
+	total.SetInt64(0)
+	for _, w := range vertexWeights {
+		if w.Sign() < 0 {
+			t.Fatalf("Found a negative weight %v", w)
+		}
+		total.Add(total, w)
+	}
+	if total.Cmp(value) != 0 {
+		t.Fatalf("The fractional clique of %v has weight %v Expected: %v", graph.Graph6Encode(g), total, value)
+	}
+	c := make(chan []int)
+	go graph.AllMaximalCliques(graph.Complement(g), c)
+	for set := range c {
+		weight := new(big.Rat)
+		for _, v := range set {
+			weight.Add(weight, vertexWeights[v])
+		}
+		if weight.Cmp(big.NewRat(1, 1)) > 0 {
+			t.Fatalf("The independent set %v of %v has weight %v in the fractional clique", set, graph.Graph6Encode(g), weight)
+		}
+	}
+}
+
+func TestFractionalChromaticNumber(t *testing.T) {
+	petersen, _ := graph.NamedGraph("Petersen")
+	grotzsch, _ := graph.NamedGraph("Grötzsch")
+	tests := []struct {
+		name     string
+		g        graph.Graph
+		expected *big.Rat
+	}{
+		{"empty graph", graph.NewDense(0, nil), big.NewRat(0, 1)},
+		{"independent set", graph.NewDense(4, nil), big.NewRat(1, 1)},
+		{"K_6", graph.CompleteGraph(6), big.NewRat(6, 1)},
+		{"C_6", graph.Cycle(6), big.NewRat(2, 1)},
+		{"C_7", graph.Cycle(7), big.NewRat(7, 3)},
+		{"C_9", graph.Cycle(9), big.NewRat(9, 4)},
+		{"Petersen graph", petersen, big.NewRat(5, 2)},
+		{"Grötzsch graph", grotzsch, big.NewRat(29, 10)},
+		{"K(7, 3)", graph.KneserGraph(7, 3), big.NewRat(7, 3)},
+	}
+	for _, test := range tests {
+		value, sets, setWeights := graph.FractionalChromaticNumber(test.g)
+		if value.Cmp(test.expected) != 0 {
+			t.Errorf("Wrong fractional chromatic number of the %v. Found: %v Expected: %v", test.name, value, test.expected)
+			continue
+		}
+		cliqueNumber, vertexWeights := graph.FractionalCliqueNumber(test.g)
+		if cliqueNumber.Cmp(test.expected) != 0 {
+			t.Errorf("Wrong fractional clique number of the %v. Found: %v Expected: %v", test.name, cliqueNumber, test.expected)
+			continue
+		}
+		checkFractional(t, test.g, value, sets, setWeights, vertexWeights)
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		g := graph.RandomGraph(int(5+seed%10), 0.5, seed)
+		value, sets, setWeights := graph.FractionalChromaticNumber(g)
+		_, vertexWeights := graph.FractionalCliqueNumber(g)
+		checkFractional(t, g, value, sets, setWeights, vertexWeights)
+		chromaticNumber, _ := graph.ChromaticNumber(g)
+		if value.Cmp(big.NewRat(int64(graph.CliqueNumber(g)), 1)) < 0 || value.Cmp(big.NewRat(int64(chromaticNumber), 1)) > 0 {
+			t.Errorf("The fractional chromatic number %v of %v isn't between the clique number and the chromatic number", value, graph.Graph6Encode(g))
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package graph
 
 import (
+	"math"
 	"math/rand"
 
 	"github.com/Tom-Johnston/mamba/comb"
+	"github.com/Tom-Johnston/mamba/ints"
 	"github.com/Tom-Johnston/mamba/sortints"
 )
 
@@ -34,6 +36,501 @@ func RandomTree(n int, seed int64) *DenseGraph {
 	return PruferDecode(code)
 }
 
+//RandomGraphNM returns a graph chosen uniformly at random from all graphs on n vertices with exactly m edges i.e. the Erdős–Rényi graph G(n, m). The pseudorandomness is determined by the seed.
+//The edges are chosen using Floyd's algorithm so this takes O(n + m) time and space and is suitable for large sparse graphs. This panics if m is larger than n(n-1)/2.
+func RandomGraphNM(n, m int, seed int64) *SparseGraph {
+	total := int64(n) * int64(n-1) / 2
+	if m < 0 || int64(m) > total {
+		panic("The number of edges must be between 0 and n(n-1)/2.")
+	}
+	r := rand.New(rand.NewSource(seed))
+	chosen := make(map[int64]struct{}, m)
+	for j := total - int64(m); j < total; j++ {
+		t := r.Int63n(j + 1)
+		if _, ok := chosen[t]; ok {
+			t = j
+		}
+		chosen[t] = struct{}{}
+	}
+	edges := make([][2]int, 0, m)
+	for k := range chosen {
+		i, j := pairFromIndex(k)
+		edges = append(edges, [2]int{i, j})
+	}
+	return sparseFromEdges(n, edges)
+}
+
+//RandomBipartiteGraph returns a random bipartite graph with parts {0, 1, ..., n1 - 1} and {n1, n1 + 1, ..., n1 + n2 - 1} where each of the n1n2 possible edges is present independently with probability p. The pseudorandomness is determined by the seed.
+//The edges are found by skipping over the missing edges so this takes O(n1 + n2 + m) time and is suitable for large sparse graphs.
+func RandomBipartiteGraph(n1, n2 int, p float64, seed int64) *SparseGraph {
+	r := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, 0)
+	bernoulliIndices(int64(n1)*int64(n2), p, r, func(k int64) {
+		edges = append(edges, [2]int{int(k / int64(n2)), n1 + int(k%int64(n2))})
+	})
+	return sparseFromEdges(n1+n2, edges)
+}
+
+//RandomRegularGraph returns a random d-regular graph on n vertices. The pseudorandomness is determined by the seed.
+//This uses the algorithm of Steger and Wormald: each vertex has d points and a pair of points chosen uniformly at random from the pairs which don't create a loop or a multiple edge is joined. If the points can't be paired any further, the algorithm starts again. The distribution is asymptotically uniform for d = o(n^(1/28)) and is close to uniform for larger d in practice. The expected running time is O(nd^2).
+//If 2d > n - 1, the complement of a random (n - 1 - d)-regular graph is returned instead since the pairing rarely succeeds for dense graphs.
+//This panics if nd is odd or d is not between 0 and n - 1.
+func RandomRegularGraph(n, d int, seed int64) *SparseGraph {
+	if d < 0 || (d >= n && n > 0) || (n*d)%2 != 0 {
+		panic("There is no d-regular graph on n vertices.")
+	}
+	if d > 0 && 2*d > n-1 {
+		h := RandomRegularGraph(n, n-1-d, seed)
+		neighbourhoods := make([]sortints.SortedInts, n)
+		for v := 0; v < n; v++ {
+			neighbourhoods[v] = sortints.Complement(n, h.Neighbourhoods[v])
+			neighbourhoods[v].Remove(v)
+		}
+		return NewSparse(n, neighbourhoods)
+	}
+	degrees := make([]int, n)
+	for i := range degrees {
+		degrees[i] = d
+	}
+	r := rand.New(rand.NewSource(seed))
+	for {
+		if edges, ok := randomPairing(degrees, r); ok {
+			return sparseFromEdges(n, edges)
+		}
+	}
+}
+
+//randomPairing tries to find a simple graph with the given degrees by joining random pairs of points as in the Steger–Wormald algorithm. It returns false if it gets stuck.
+func randomPairing(degrees []int, r *rand.Rand) ([][2]int, bool) {
+	points := make([]int, 0, ints.Sum(degrees))
+	for v, d := range degrees {
+		for i := 0; i < d; i++ {
+			points = append(points, v)
+		}
+	}
+	edges := make([][2]int, 0, len(points)/2)
+	present := make(map[[2]int]struct{}, len(points)/2)
+	isEdge := func(u, v int) bool {
+		if u > v {
+			u, v = v, u
+		}
+		_, ok := present[[2]int{u, v}]
+		return ok
+	}
+	failures := 0
+	for len(points) > 0 {
+		//Choose a random pair of points and join them if it doesn't create a loop or a multiple edge.
+		k := len(points)
+		i := r.Intn(k)
+		j := r.Intn(k - 1)
+		if j >= i {
+			j++
+		}
+		u, v := points[i], points[j]
+		if u == v || isEdge(u, v) {
+			failures++
+			if failures < k {
+				continue
+			}
+			//There have been a lot of failures so check that some pair of the remaining points can still be joined.
+			failures = 0
+			vertices := sortints.NewSortedInts(points...)
+			suitable := false
+		search:
+			for a, x := range vertices {
+				for _, y := range vertices[:a] {
+					if !isEdge(x, y) {
+						suitable = true
+						break search
+					}
+				}
+			}
+			if !suitable {
+				return nil, false
+			}
+			continue
+		}
+		failures = 0
+		if u > v {
+			u, v = v, u
+		}
+		present[[2]int{u, v}] = struct{}{}
+		edges = append(edges, [2]int{u, v})
+		//Remove the two points by moving the last two points into their places.
+		if i < j {
+			i, j = j, i
+		}
+		points[i] = points[k-1]
+		points[j] = points[k-2]
+		points = points[:k-2]
+	}
+	return edges, true
+}
+
+//RandomDegreeSequenceGraph returns a random graph where the vertex v has degree degrees[v]. The pseudorandomness is determined by the seed.
+//A graph with the given degrees is constructed using the Havel–Hakimi algorithm and then 10m random double edge swaps, which replace the edges ab and cd by ac and bd if they aren't already present, are attempted. This always terminates but the distribution is only approximately uniform.
+//This panics if there is no graph with the given degree sequence.
+func RandomDegreeSequenceGraph(degrees []int, seed int64) *SparseGraph {
+	n := len(degrees)
+	edges := havelHakimi(degrees)
+	r := rand.New(rand.NewSource(seed))
+	m := len(edges)
+	if m < 2 {
+		return sparseFromEdges(n, edges)
+	}
+	present := make(map[[2]int]struct{}, m)
+	key := func(u, v int) [2]int {
+		if u > v {
+			return [2]int{v, u}
+		}
+		return [2]int{u, v}
+	}
+	for _, e := range edges {
+		present[e] = struct{}{}
+	}
+	for attempt := 0; attempt < 10*m; attempt++ {
+		i, j := r.Intn(m), r.Intn(m)
+		a, b := edges[i][0], edges[i][1]
+		c, d := edges[j][0], edges[j][1]
+		if r.Intn(2) == 1 {
+			c, d = d, c
+		}
+		if a == c || a == d || b == c || b == d {
+			continue
+		}
+		if _, ok := present[key(a, c)]; ok {
+			continue
+		}
+		if _, ok := present[key(b, d)]; ok {
+			continue
+		}
+		delete(present, edges[i])
+		delete(present, edges[j])
+		edges[i] = key(a, c)
+		edges[j] = key(b, d)
+		present[edges[i]] = struct{}{}
+		present[edges[j]] = struct{}{}
+	}
+	return sparseFromEdges(n, edges)
+}
+
+//havelHakimi returns the edges of a graph with the given degrees. It repeatedly joins a vertex of largest remaining degree to the vertices with the next largest remaining degrees and panics if this fails.
+func havelHakimi(degrees []int) [][2]int {
+	n := len(degrees)
+	remaining := make([]int, n)
+	copy(remaining, degrees)
+	maxDegree := 0
+	for _, d := range degrees {
+		if d < 0 || d >= n {
+			panic("The degree sequence is not graphical.")
+		}
+		if d > maxDegree {
+			maxDegree = d
+		}
+	}
+	//buckets[d] holds the vertices with remaining degree d.
+	buckets := make([][]int, maxDegree+1)
+	for v, d := range degrees {
+		buckets[d] = append(buckets[d], v)
+	}
+	edges := make([][2]int, 0, ints.Sum(degrees)/2)
+	chosen := make([]int, 0, maxDegree)
+	for {
+		for maxDegree > 0 && len(buckets[maxDegree]) == 0 {
+			maxDegree--
+		}
+		if maxDegree == 0 {
+			break
+		}
+		v := buckets[maxDegree][len(buckets[maxDegree])-1]
+		buckets[maxDegree] = buckets[maxDegree][:len(buckets[maxDegree])-1]
+		d := remaining[v]
+		remaining[v] = 0
+		//Take the d vertices with the largest remaining degrees.
+		chosen = chosen[:0]
+		for k := maxDegree; k > 0 && len(chosen) < d; k-- {
+			for len(buckets[k]) > 0 && len(chosen) < d {
+				chosen = append(chosen, buckets[k][len(buckets[k])-1])
+				buckets[k] = buckets[k][:len(buckets[k])-1]
+			}
+		}
+		if len(chosen) < d {
+			panic("The degree sequence is not graphical.")
+		}
+		for _, u := range chosen {
+			remaining[u]--
+			buckets[remaining[u]] = append(buckets[remaining[u]], u)
+			if u < v {
+				edges = append(edges, [2]int{u, v})
+			} else {
+				edges = append(edges, [2]int{v, u})
+			}
+		}
+	}
+	return edges
+}
+
+//BarabasiAlbertGraph returns a random graph on n vertices grown by preferential attachment. The pseudorandomness is determined by the seed.
+//The graph starts as the star with centre 0 and leaves 1, 2, ..., m and each new vertex is joined to m distinct existing vertices where each is chosen with probability proportional to its degree. The graph has m(n - m) edges.
+//This panics if m is not between 1 and n - 1.
+func BarabasiAlbertGraph(n, m int, seed int64) *SparseGraph {
+	if m < 1 || m >= n {
+		panic("m must be between 1 and n - 1.")
+	}
+	r := rand.New(rand.NewSource(seed))
+	edges := make([][2]int, 0, m*(n-m))
+	//Each vertex appears in ends once for each edge it is in so choosing a uniform element of ends is choosing a vertex proportional to its degree.
+	ends := make([]int, 0, 2*m*(n-m))
+	for v := 1; v <= m; v++ {
+		edges = append(edges, [2]int{0, v})
+		ends = append(ends, 0, v)
+	}
+	targets := make([]int, 0, m)
+	for v := m + 1; v < n; v++ {
+		targets = targets[:0]
+		for len(targets) < m {
+			u := ends[r.Intn(len(ends))]
+			if !containsInt(targets, u) {
+				targets = append(targets, u)
+			}
+		}
+		for _, u := range targets {
+			edges = append(edges, [2]int{u, v})
+			ends = append(ends, u, v)
+		}
+	}
+	return sparseFromEdges(n, edges)
+}
+
+//WattsStrogatzGraph returns a random small-world graph on n vertices. The pseudorandomness is determined by the seed.
+//The graph starts as the ring lattice where each vertex i is joined to the k vertices i ± 1, i ± 2, ..., i ± k/2 (mod n). Then, for j = 1, 2, ..., k/2 and each vertex i, the edge from i to i + j is replaced with probability p by an edge from i to a vertex chosen uniformly at random from the vertices which aren't i or already joined to i. The graph has nk/2 edges.
+//This panics if k is odd or k is not between 0 and n - 1.
+func WattsStrogatzGraph(n, k int, p float64, seed int64) *SparseGraph {
+	if k < 0 || k%2 != 0 || (k >= n && k > 0) {
+		panic("k must be even and between 0 and n - 1.")
+	}
+	r := rand.New(rand.NewSource(seed))
+	present := make(map[[2]int]struct{}, n*k/2)
+	key := func(u, v int) [2]int {
+		if u > v {
+			return [2]int{v, u}
+		}
+		return [2]int{u, v}
+	}
+	for j := 1; j <= k/2; j++ {
+		for i := 0; i < n; i++ {
+			present[key(i, (i+j)%n)] = struct{}{}
+		}
+	}
+	degrees := make([]int, n)
+	for i := range degrees {
+		degrees[i] = k
+	}
+	for j := 1; j <= k/2; j++ {
+		for i := 0; i < n; i++ {
+			if r.Float64() >= p || degrees[i] >= n-1 {
+				continue
+			}
+			v := (i + j) % n
+			w := r.Intn(n)
+			for {
+				if _, ok := present[key(i, w)]; w != i && !ok {
+					break
+				}
+				w = r.Intn(n)
+			}
+			delete(present, key(i, v))
+			present[key(i, w)] = struct{}{}
+			degrees[v]--
+			degrees[w]++
+		}
+	}
+	edges := make([][2]int, 0, len(present))
+	for e := range present {
+		edges = append(edges, e)
+	}
+	return sparseFromEdges(n, edges)
+}
+
+//RandomGeometricGraph returns a random geometric graph on n points chosen uniformly at random from the unit square where two points are joined if the distance between them is at most radius. If torus is true, the opposite sides of the square are identified so the distance wraps around. The pseudorandomness is determined by the seed.
+//The points are also returned and the point points[v] is the position of the vertex v. The points are placed in a grid of cells of width at least radius and only the points in neighbouring cells are compared so the expected running time is O(n + m) when the radius isn't too large.
+func RandomGeometricGraph(n int, radius float64, torus bool, seed int64) (*SparseGraph, [][2]float64) {
+	r := rand.New(rand.NewSource(seed))
+	points := make([][2]float64, n)
+	for v := range points {
+		points[v] = [2]float64{r.Float64(), r.Float64()}
+	}
+
+	//Use at most about n cells so the grid isn't too large for small radii.
+	cells := int(math.Sqrt(float64(n)))
+	if radius > 0 && 1/radius < float64(cells) {
+		cells = int(1 / radius)
+	}
+	if cells < 1 {
+		cells = 1
+	}
+	cellOf := func(x float64) int {
+		c := int(x * float64(cells))
+		if c >= cells {
+			c = cells - 1
+		}
+		return c
+	}
+	grid := make([][]int, cells*cells)
+	for v, pt := range points {
+		c := cellOf(pt[0])*cells + cellOf(pt[1])
+		grid[c] = append(grid[c], v)
+	}
+
+	distance := func(a, b float64) float64 {
+		d := math.Abs(a - b)
+		if torus && d > 0.5 {
+			d = 1 - d
+		}
+		return d
+	}
+	r2 := radius * radius
+	edges := make([][2]int, 0)
+	neighbouringCells := make([]int, 0, 9)
+	for cx := 0; cx < cells; cx++ {
+		for cy := 0; cy < cells; cy++ {
+			//Find the distinct cells next to (cx, cy) including itself.
+			neighbouringCells = neighbouringCells[:0]
+			for dx := -1; dx <= 1; dx++ {
+				for dy := -1; dy <= 1; dy++ {
+					x, y := cx+dx, cy+dy
+					if torus {
+						x = (x + cells) % cells
+						y = (y + cells) % cells
+					} else if x < 0 || x >= cells || y < 0 || y >= cells {
+						continue
+					}
+					if c := x*cells + y; !containsInt(neighbouringCells, c) {
+						neighbouringCells = append(neighbouringCells, c)
+					}
+				}
+			}
+			for _, v := range grid[cx*cells+cy] {
+				for _, c := range neighbouringCells {
+					for _, u := range grid[c] {
+						if u >= v {
+							continue
+						}
+						dx := distance(points[u][0], points[v][0])
+						dy := distance(points[u][1], points[v][1])
+						if dx*dx+dy*dy <= r2 {
+							edges = append(edges, [2]int{u, v})
+						}
+					}
+				}
+			}
+		}
+	}
+	return sparseFromEdges(n, edges), points
+}
+
+//StochasticBlockModel returns a random graph where the vertices are split into blocks of the given sizes and an edge between a vertex in block a and a vertex in block b is present independently with probability p[a][b]. The pseudorandomness is determined by the seed.
+//The first sizes[0] vertices are in block 0, the next sizes[1] vertices are in block 1 and so on. The edges are found by skipping over the missing edges so this takes O(n + m + k^2) time where k is the number of blocks.
+//This panics if p is not a symmetric len(sizes) x len(sizes) matrix.
+func StochasticBlockModel(sizes []int, p [][]float64, seed int64) *SparseGraph {
+	k := len(sizes)
+	if len(p) != k {
+		panic("p must be a symmetric len(sizes) x len(sizes) matrix.")
+	}
+	for a := range p {
+		if len(p[a]) != k {
+			panic("p must be a symmetric len(sizes) x len(sizes) matrix.")
+		}
+		for b := 0; b < a; b++ {
+			if p[a][b] != p[b][a] {
+				panic("p must be a symmetric len(sizes) x len(sizes) matrix.")
+			}
+		}
+	}
+	r := rand.New(rand.NewSource(seed))
+	starts := make([]int, k+1)
+	for a, s := range sizes {
+		starts[a+1] = starts[a] + s
+	}
+	edges := make([][2]int, 0)
+	for a := 0; a < k; a++ {
+		start := starts[a]
+		s := int64(sizes[a])
+		bernoulliIndices(s*(s-1)/2, p[a][a], r, func(x int64) {
+			i, j := pairFromIndex(x)
+			edges = append(edges, [2]int{start + i, start + j})
+		})
+		for b := a + 1; b < k; b++ {
+			t := int64(sizes[b])
+			other := starts[b]
+			bernoulliIndices(s*t, p[a][b], r, func(x int64) {
+				edges = append(edges, [2]int{start + int(x/t), other + int(x%t)})
+			})
+		}
+	}
+	return sparseFromEdges(starts[k], edges)
+}
+
+//pairFromIndex returns the pair i < j in position k of the order 01, 02, 12, 03, 13, 23... which is the order of the edges in DenseGraph.
+func pairFromIndex(k int64) (int, int) {
+	j := int((1 + math.Sqrt(float64(1+8*k))) / 2)
+	//Correct any rounding errors in the square root.
+	for int64(j)*int64(j-1)/2 > k {
+		j--
+	}
+	for int64(j+1)*int64(j)/2 <= k {
+		j++
+	}
+	return int(k - int64(j)*int64(j-1)/2), j
+}
+
+//bernoulliIndices calls f in increasing order on each element of {0, 1, ..., total - 1} which is chosen independently with probability p.
+//The gap between chosen elements is geometrically distributed so this takes time proportional to the number of chosen elements.
+func bernoulliIndices(total int64, p float64, r *rand.Rand, f func(k int64)) {
+	if p <= 0 {
+		return
+	}
+	if p >= 1 {
+		for k := int64(0); k < total; k++ {
+			f(k)
+		}
+		return
+	}
+	logQ := math.Log(1 - p)
+	k := int64(-1)
+	for {
+		skip := math.Floor(math.Log(1-r.Float64()) / logQ)
+		if float64(k)+1+skip >= float64(total) {
+			return
+		}
+		k += 1 + int64(skip)
+		f(k)
+	}
+}
+
+//containsInt returns true if the unsorted slice a contains x.
+func containsInt(a []int, x int) bool {
+	for _, y := range a {
+		if y == x {
+			return true
+		}
+	}
+	return false
+}
+
+//sparseFromEdges returns the *SparseGraph on n vertices with the given edges.
+func sparseFromEdges(n int, edges [][2]int) *SparseGraph {
+	neighbourhoods := make([]sortints.SortedInts, n)
+	for i := range neighbourhoods {
+		neighbourhoods[i] = []int{}
+	}
+	for _, e := range edges {
+		neighbourhoods[e[0]] = append(neighbourhoods[e[0]], e[1])
+		neighbourhoods[e[1]] = append(neighbourhoods[e[1]], e[0])
+	}
+	return NewSparse(n, neighbourhoods)
+}
+
 //Specific Graphs
 
 //CompleteGraph returns a copy of the complete graph on n vertices.
@@ -0,0 +1,221 @@
+package graph_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+//checkSimple checks that the neighbourhoods of g are symmetric and don't contain loops.
+func checkSimple(t *testing.T, g *graph.SparseGraph) {
+	t.Helper()
+	m := 0
+	degrees := g.Degrees()
+	for v := 0; v < g.N(); v++ {
+		for _, u := range g.Neighbours(v) {
+			if u == v || !g.IsEdge(u, v) {
+				t.Fatalf("The graph isn't simple at the vertex %v", v)
+			}
+		}
+		m += degrees[v]
+	}
+	if m != 2*g.M() {
+		t.Fatalf("Wrong number of edges. Found: %v Expected: %v", g.M(), m/2)
+	}
+}
+
+func TestRandomGraphNM(t *testing.T) {
+	for _, test := range [][2]int{{0, 0}, {1, 0}, {2, 1}, {10, 0}, {10, 45}, {100, 300}, {100000, 200000}} {
+		n, m := test[0], test[1]
+		g := graph.RandomGraphNM(n, m, 1)
+		checkSimple(t, g)
+		if g.N() != n || g.M() != m {
+			t.Errorf("Wrong size. Found: (%v, %v) Expected: (%v, %v)", g.N(), g.M(), n, m)
+		}
+	}
+	if graph.Graph6Encode(graph.RandomGraphNM(20, 50, 7)) != graph.Graph6Encode(graph.RandomGraphNM(20, 50, 7)) {
+		t.Errorf("The same seed gave different graphs")
+	}
+}
+
+func TestRandomBipartiteGraph(t *testing.T) {
+	g := graph.RandomBipartiteGraph(3, 4, 1, 1)
+	if g.M() != 12 {
+		t.Errorf("Wrong number of edges for p = 1. Found: %v Expected: 12", g.M())
+	}
+	g = graph.RandomBipartiteGraph(3, 4, 0, 1)
+	if g.M() != 0 {
+		t.Errorf("Wrong number of edges for p = 0. Found: %v Expected: 0", g.M())
+	}
+	g = graph.RandomBipartiteGraph(1000, 2000, 0.005, 1)
+	checkSimple(t, g)
+	for v := 0; v < g.N(); v++ {
+		for _, u := range g.Neighbours(v) {
+			if (u < 1000) == (v < 1000) {
+				t.Fatalf("The edge %v%v is inside a part", u, v)
+			}
+		}
+	}
+	//The expected number of edges is 10000 with standard deviation about 100.
+	if g.M() < 9500 || g.M() > 10500 {
+		t.Errorf("Unlikely number of edges: %v", g.M())
+	}
+}
+
+func TestRandomRegularGraph(t *testing.T) {
+	for _, test := range [][2]int{{0, 0}, {1, 0}, {4, 3}, {10, 3}, {10, 8}, {11, 6}, {50, 7}, {100000, 3}} {
+		n, d := test[0], test[1]
+		g := graph.RandomRegularGraph(n, d, 1)
+		checkSimple(t, g)
+		for v, degree := range g.Degrees() {
+			if degree != d {
+				t.Fatalf("The vertex %v of the %v-regular graph on %v vertices has degree %v", v, d, n, degree)
+			}
+		}
+	}
+
+	//There are 60 labelled 6-cycles and 10 labelled pairs of triangles.
+	cycles := 0
+	trials := 2000
+	for seed := 0; seed < trials; seed++ {
+		g := graph.RandomRegularGraph(6, 2, int64(seed))
+		if len(graph.ConnectedComponents(g)) == 1 {
+			cycles++
+		}
+	}
+	if cycles < 1640 || cycles > 1790 {
+		t.Errorf("Unlikely number of 6-cycles. Found: %v Expected: about %v", cycles, trials*60/70)
+	}
+}
+
+func TestRandomDegreeSequenceGraph(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		degrees := graph.RandomGraph(30, 0.3, seed).Degrees()
+		g := graph.RandomDegreeSequenceGraph(degrees, seed)
+		checkSimple(t, g)
+		if !ints.Equal(g.Degrees(), degrees) {
+			t.Fatalf("Wrong degrees. Found: %v Expected: %v", g.Degrees(), degrees)
+		}
+	}
+
+	for _, degrees := range [][]int{{3, 3, 1, 1}, {1, 1, 1}, {4, 1, 1, 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v is not graphical but no panic", degrees)
+				}
+			}()
+			graph.RandomDegreeSequenceGraph(degrees, 1)
+		}()
+	}
+}
+
+func TestBarabasiAlbertGraph(t *testing.T) {
+	for _, test := range [][2]int{{2, 1}, {10, 9}, {1000, 1}, {1000, 3}, {100000, 2}} {
+		n, m := test[0], test[1]
+		g := graph.BarabasiAlbertGraph(n, m, 1)
+		checkSimple(t, g)
+		if g.N() != n || g.M() != m*(n-m) {
+			t.Errorf("Wrong size. Found: (%v, %v) Expected: (%v, %v)", g.N(), g.M(), n, m*(n-m))
+		}
+	}
+	//Every vertex after the star has degree at least m and the graph is connected.
+	g := graph.BarabasiAlbertGraph(500, 4, 2)
+	for v, d := range g.Degrees() {
+		if v > 4 && d < 4 {
+			t.Errorf("The vertex %v has degree %v", v, d)
+		}
+	}
+	if len(graph.ConnectedComponents(g)) != 1 {
+		t.Errorf("The graph isn't connected")
+	}
+}
+
+func TestWattsStrogatzGraph(t *testing.T) {
+	//With p = 0 the graph is the ring lattice.
+	g := graph.WattsStrogatzGraph(10, 4, 0, 1)
+	if graph.Graph6Encode(g) != graph.Graph6Encode(graph.CirculantGraph(10, 1, 2)) {
+		t.Errorf("WattsStrogatzGraph(10, 4, 0) is not the ring lattice")
+	}
+	for _, p := range []float64{0.1, 0.5, 1} {
+		for _, test := range [][2]int{{0, 0}, {5, 4}, {100, 6}, {100000, 10}} {
+			n, k := test[0], test[1]
+			g := graph.WattsStrogatzGraph(n, k, p, 1)
+			checkSimple(t, g)
+			if g.N() != n || g.M() != n*k/2 {
+				t.Errorf("Wrong size. Found: (%v, %v) Expected: (%v, %v)", g.N(), g.M(), n, n*k/2)
+			}
+		}
+	}
+}
+
+func TestRandomGeometricGraph(t *testing.T) {
+	for _, torus := range []bool{false, true} {
+		for _, radius := range []float64{0, 0.01, 0.1, 0.3, 0.6, 2} {
+			n := 400
+			g, points := graph.RandomGeometricGraph(n, radius, torus, 1)
+			checkSimple(t, g)
+			for v := 0; v < n; v++ {
+				for u := 0; u < v; u++ {
+					dx := math.Abs(points[u][0] - points[v][0])
+					dy := math.Abs(points[u][1] - points[v][1])
+					if torus {
+						dx = math.Min(dx, 1-dx)
+						dy = math.Min(dy, 1-dy)
+					}
+					if (dx*dx+dy*dy <= radius*radius) != g.IsEdge(u, v) {
+						t.Fatalf("Wrong edge %v%v for radius %v and torus %v", u, v, radius, torus)
+					}
+				}
+			}
+		}
+	}
+	//The expected degree on the torus is pi r^2 (n - 1).
+	g, _ := graph.RandomGeometricGraph(100000, 0.005, true, 1)
+	if average := 2 * float64(g.M()) / float64(g.N()); math.Abs(average-math.Pi*0.005*0.005*99999) > 0.2 {
+		t.Errorf("Unlikely average degree: %v", average)
+	}
+}
+
+func TestStochasticBlockModel(t *testing.T) {
+	//Two blocks with p = 1 inside the blocks and p = 0 between them are two cliques.
+	g := graph.StochasticBlockModel([]int{3, 4}, [][]float64{{1, 0}, {0, 1}}, 1)
+	if g.M() != 9 || len(graph.ConnectedComponents(g)) != 2 {
+		t.Errorf("Expected two cliques")
+	}
+	//Swapping the probabilities gives the complete bipartite graph.
+	g = graph.StochasticBlockModel([]int{3, 4}, [][]float64{{0, 1}, {1, 0}}, 1)
+	if graph.Graph6Encode(g) != graph.Graph6Encode(graph.CompletePartiteGraph(3, 4)) {
+		t.Errorf("Expected K_{3, 4}")
+	}
+	g = graph.StochasticBlockModel([]int{20000, 30000, 50000}, [][]float64{{1e-3, 1e-5, 0}, {1e-5, 1e-3, 1e-5}, {0, 1e-5, 1e-4}}, 1)
+	checkSimple(t, g)
+	//Count the edges inside and between the blocks.
+	block := func(v int) int {
+		if v < 20000 {
+			return 0
+		} else if v < 50000 {
+			return 1
+		}
+		return 2
+	}
+	counts := [3][3]int{}
+	for v := 0; v < g.N(); v++ {
+		for _, u := range g.Neighbours(v) {
+			if u < v {
+				a, b := block(u), block(v)
+				counts[a][b]++
+			}
+		}
+	}
+	expected := [3][3]float64{{1e-3 * 20000 * 19999 / 2, 1e-5 * 20000 * 30000, 0}, {0, 1e-3 * 30000 * 29999 / 2, 1e-5 * 30000 * 50000}, {0, 0, 1e-4 * 50000 * 49999 / 2}}
+	for a := 0; a < 3; a++ {
+		for b := a; b < 3; b++ {
+			if math.Abs(float64(counts[a][b])-expected[a][b]) > 5*math.Sqrt(expected[a][b])+1 {
+				t.Errorf("Unlikely number of edges between blocks %v and %v. Found: %v Expected: about %v", a, b, counts[a][b], expected[a][b])
+			}
+		}
+	}
+}
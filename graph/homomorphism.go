@@ -0,0 +1,308 @@
+package graph
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//homFactor is a function from the assignments of the vertices vars of H to vertices of G to the integers. This is used when counting homomorphisms.
+//The value for the assignment x is stored in values[x[0] + n*x[1] + n^2*x[2] + ...] where n is the number of vertices of G.
+type homFactor struct {
+	vars   []int
+	values []*big.Int
+}
+
+//HomomorphismCount returns the number of homomorphisms from h to g i.e. the number of maps f from the vertices of h to the vertices of g such that f(u)f(v) is an edge of g whenever uv is an edge of h. The map f does not need to be injective.
+//For example, the number of homomorphisms from h to the complete graph on k vertices is the number of proper k-colourings of h and the number of homomorphisms from an edge to g is 2 g.M().
+//The count is found by variable elimination which is equivalent to dynamic programming over a tree decomposition of h. The vertices of h are eliminated greedily in order of minimum degree and, if the resulting tree decomposition has width w, the running time is roughly O(h.N() g.N()^(w + 1)) and the memory used is O(g.N()^w). This panics if g.N()^w doesn't fit in an int. This is fast when h has small treewidth e.g. when h is a tree or a cycle.
+func HomomorphismCount(h, g Graph) *big.Int {
+	k := h.N()
+	n := g.N()
+	if k == 0 {
+		return big.NewInt(1)
+	}
+	if n == 0 {
+		return big.NewInt(0)
+	}
+
+	//The adjacency matrix of g is the factor for every edge of h.
+	adjacency := make([]*big.Int, n*n)
+	zero := big.NewInt(0)
+	one := big.NewInt(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			adjacency[i+n*j] = zero
+		}
+		for _, j := range g.Neighbours(i) {
+			adjacency[i+n*j] = one
+		}
+	}
+
+	//factorsOf[v] contains the indices of the current factors which involve v.
+	factors := make([]*homFactor, 0, h.M())
+	alive := make([]bool, 0, h.M())
+	factorsOf := make([][]int, k)
+	neighbours := make([]sortints.SortedInts, k)
+	for v := 0; v < k; v++ {
+		neighbours[v] = sortints.NewSortedInts(h.Neighbours(v)...)
+		for _, u := range neighbours[v] {
+			if u > v {
+				break
+			}
+			factorsOf[u] = append(factorsOf[u], len(factors))
+			factorsOf[v] = append(factorsOf[v], len(factors))
+			factors = append(factors, &homFactor{vars: []int{u, v}, values: adjacency})
+			alive = append(alive, true)
+		}
+	}
+
+	result := big.NewInt(1)
+	eliminated := make([]bool, k)
+	for step := 0; step < k; step++ {
+		//Choose the vertex of minimum degree in the current graph.
+		v := -1
+		for u := 0; u < k; u++ {
+			if !eliminated[u] && (v == -1 || len(neighbours[u]) < len(neighbours[v])) {
+				v = u
+			}
+		}
+		eliminated[v] = true
+
+		//The neighbours of v form a clique after eliminating v.
+		vars := neighbours[v]
+		for _, u := range vars {
+			neighbours[u].Remove(v)
+			for _, w := range vars {
+				if w != u {
+					neighbours[u].Add(w)
+				}
+			}
+		}
+
+		bucket := make([]*homFactor, 0, len(factorsOf[v]))
+		for _, i := range factorsOf[v] {
+			if alive[i] {
+				bucket = append(bucket, factors[i])
+				alive[i] = false
+			}
+		}
+		f := eliminate(v, vars, bucket, n)
+		if len(vars) == 0 {
+			result.Mul(result, f.values[0])
+			if result.Sign() == 0 {
+				return result
+			}
+			continue
+		}
+		for _, u := range vars {
+			factorsOf[u] = append(factorsOf[u], len(factors))
+		}
+		factors = append(factors, f)
+		alive = append(alive, true)
+	}
+	return result
+}
+
+//eliminate returns the factor over vars which is the sum over the assignments of v of the product of the factors in bucket. The vars are the vertices other than v which appear in the factors of the bucket.
+func eliminate(v int, vars []int, bucket []*homFactor, n int) *homFactor {
+	maxInt := int(^uint(0) >> 1)
+	size := 1
+	for range vars {
+		if size > maxInt/n {
+			panic(fmt.Sprintf("The factor over %v vertices of h has %v^%v entries which is more than the largest int.", len(vars), n, len(vars)))
+		}
+		size *= n
+	}
+
+	//For each factor find the stride of each variable in the factor's table.
+	strides := make([][]int, len(bucket))
+	vStrides := make([]int, len(bucket))
+	for i, f := range bucket {
+		strides[i] = make([]int, len(vars))
+		stride := 1
+		for _, u := range f.vars {
+			if u == v {
+				vStrides[i] = stride
+			} else {
+				strides[i][sort.SearchInts(vars, u)] = stride
+			}
+			stride *= n
+		}
+	}
+
+	values := make([]*big.Int, size)
+	assignment := make([]int, len(vars))
+	base := make([]int, len(bucket))
+	product := new(big.Int)
+	for index := 0; index < size; index++ {
+		//Find the index of the current assignment in each factor.
+		for i := range bucket {
+			base[i] = 0
+			for j, x := range assignment {
+				base[i] += x * strides[i][j]
+			}
+		}
+		sum := new(big.Int)
+	xLoop:
+		for x := 0; x < n; x++ {
+			product.SetInt64(1)
+			for i, f := range bucket {
+				value := f.values[base[i]+x*vStrides[i]]
+				if value.Sign() == 0 {
+					continue xLoop
+				}
+				product.Mul(product, value)
+			}
+			sum.Add(sum, product)
+		}
+		values[index] = sum
+
+		//Move to the next assignment.
+		for j := range assignment {
+			assignment[j]++
+			if assignment[j] < n {
+				break
+			}
+			assignment[j] = 0
+		}
+	}
+	return &homFactor{vars: vars, values: values}
+}
+
+//HomomorphismDensity returns the probability that a uniformly random map from the vertices of h to the vertices of g is a homomorphism i.e. hom(h, g) / g.N()^h.N().
+//See HomomorphismCount for details of how the homomorphisms are counted.
+func HomomorphismDensity(h, g Graph) *big.Rat {
+	total := new(big.Int).Exp(big.NewInt(int64(g.N())), big.NewInt(int64(h.N())), nil)
+	if total.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(HomomorphismCount(h, g), total)
+}
+
+//IsHColorable returns true if there is a homomorphism from g to h (known as an h-colouring of g) and an example homomorphism f where f[v] is the image of the vertex v, else it returns false, nil.
+//When h is the complete graph on k vertices, this is the same as IsKColorable.
+//This uses a backtracking search with forward checking. The next vertex to colour is the one with the fewest possible colours left, breaking ties by the degree.
+func IsHColorable(g, h Graph) (ok bool, homomorphism []int) {
+	n := g.N()
+	k := h.N()
+	if n == 0 {
+		return true, []int{}
+	}
+	if k == 0 {
+		return false, nil
+	}
+
+	//The sets of possible colours are stored as bitsets.
+	words := (k + 63) / 64
+	hNeighbours := make([][]uint64, k)
+	for c := 0; c < k; c++ {
+		hNeighbours[c] = make([]uint64, words)
+		for _, d := range h.Neighbours(c) {
+			hNeighbours[c][d/64] |= 1 << uint(d%64)
+		}
+	}
+	gNeighbours := make([][]int, n)
+	for v := range gNeighbours {
+		gNeighbours[v] = g.Neighbours(v)
+	}
+	degrees := g.Degrees()
+
+	//domains[d] holds the possible colours of each vertex at depth d of the search.
+	domains := make([][][]uint64, n+1)
+	for d := range domains {
+		domains[d] = make([][]uint64, n)
+		for v := range domains[d] {
+			domains[d][v] = make([]uint64, words)
+		}
+	}
+	for v := 0; v < n; v++ {
+		for c := 0; c < k; c++ {
+			domains[0][v][c/64] |= 1 << uint(c%64)
+		}
+	}
+
+	colouring := make([]int, n)
+	for i := range colouring {
+		colouring[i] = -1
+	}
+
+	popCount := func(set []uint64) int {
+		count := 0
+		for _, w := range set {
+			count += bits.OnesCount64(w)
+		}
+		return count
+	}
+
+	var search func(depth int) bool
+	search = func(depth int) bool {
+		if depth == n {
+			return true
+		}
+		current := domains[depth]
+		//Choose the most constrained vertex.
+		v := -1
+		vSize := 0
+		for u := 0; u < n; u++ {
+			if colouring[u] != -1 {
+				continue
+			}
+			size := popCount(current[u])
+			if size == 0 {
+				return false
+			}
+			if v == -1 || size < vSize || (size == vSize && degrees[u] > degrees[v]) {
+				v = u
+				vSize = size
+			}
+		}
+
+		next := domains[depth+1]
+		for w, word := range current[v] {
+			for word != 0 {
+				c := 64*w + bits.TrailingZeros64(word)
+				word &= word - 1
+
+				//Restrict the colours of the uncoloured neighbours.
+				for u := range next {
+					copy(next[u], current[u])
+				}
+				possible := true
+				for _, u := range gNeighbours[v] {
+					if colouring[u] != -1 {
+						continue
+					}
+					empty := true
+					for i := range next[u] {
+						next[u][i] &= hNeighbours[c][i]
+						if next[u][i] != 0 {
+							empty = false
+						}
+					}
+					if empty {
+						possible = false
+						break
+					}
+				}
+				if !possible {
+					continue
+				}
+				colouring[v] = c
+				if search(depth + 1) {
+					return true
+				}
+				colouring[v] = -1
+			}
+		}
+		return false
+	}
+
+	if !search(0) {
+		return false, nil
+	}
+	return true, colouring
+}
@@ -0,0 +1,144 @@
+package graph_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//bruteForceHomomorphisms counts the homomorphisms from h to g by checking every map.
+func bruteForceHomomorphisms(h, g graph.Graph) int64 {
+	f := make([]int, h.N())
+	var count func(v int) int64
+	count = func(v int) int64 {
+		if v == h.N() {
+			return 1
+		}
+		var total int64
+		for x := 0; x < g.N(); x++ {
+			ok := true
+			for _, u := range h.Neighbours(v) {
+				if u < v && !g.IsEdge(f[u], x) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				f[v] = x
+				total += count(v + 1)
+			}
+		}
+		return total
+	}
+	return count(0)
+}
+
+func TestHomomorphismCount(t *testing.T) {
+	tests := []struct {
+		h, g     graph.Graph
+		expected int64
+	}{
+		//The chromatic polynomial of C5 at 3 is 2^5 - 2.
+		{graph.Cycle(5), graph.CompleteGraph(3), 30},
+		{graph.CompleteGraph(2), graph.Cycle(7), 14},
+		{graph.CompleteGraph(4), graph.CompleteGraph(3), 0},
+		{graph.NewDense(3, nil), graph.CompleteGraph(4), 64},
+		{graph.NewDense(0, nil), graph.CompleteGraph(4), 1},
+		//hom(C4, G) is the trace of the fourth power of the adjacency matrix. For K4 the eigenvalues are 3, -1, -1, -1.
+		{graph.Cycle(4), graph.CompleteGraph(4), 84},
+	}
+	for _, test := range tests {
+		if c := graph.HomomorphismCount(test.h, test.g); c.Cmp(big.NewInt(test.expected)) != 0 {
+			t.Errorf("Wrong number of homomorphisms from %v to %v. Found: %v Expected: %v", graph.Graph6Encode(test.h), graph.Graph6Encode(test.g), c, test.expected)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		h := randomGraph(1+r.Intn(6), 0.4, r)
+		g := randomGraph(1+r.Intn(6), 0.5, r)
+		expected := bruteForceHomomorphisms(h, g)
+		if c := graph.HomomorphismCount(h, g); c.Cmp(big.NewInt(expected)) != 0 {
+			t.Fatalf("Wrong number of homomorphisms from %v to %v. Found: %v Expected: %v", graph.Graph6Encode(h), graph.Graph6Encode(g), c, expected)
+		}
+	}
+
+	//The number of homomorphisms from a path with k edges into a d-regular graph on n vertices is n d^k.
+	if c := graph.HomomorphismCount(graph.Path(40), graph.Cycle(10)); c.Cmp(new(big.Int).Lsh(big.NewInt(10), 39)) != 0 {
+		t.Errorf("Wrong number of homomorphisms from P40 to C10. Found: %v", c)
+	}
+
+	//The density of an edge in K3 is 6/9.
+	if d := graph.HomomorphismDensity(graph.CompleteGraph(2), graph.CompleteGraph(3)); d.Cmp(big.NewRat(2, 3)) != 0 {
+		t.Errorf("Wrong homomorphism density. Found: %v Expected: 2/3", d)
+	}
+
+	//The factor after eliminating a vertex of K_70 has 1000^69 entries.
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic for a factor which is too large")
+			}
+		}()
+		graph.HomomorphismCount(graph.CompleteGraph(70), graph.CompleteGraph(1000))
+	}()
+}
+
+func TestIsHColorable(t *testing.T) {
+	tests := []struct {
+		g, h     graph.Graph
+		expected bool
+	}{
+		{graph.Cycle(5), graph.CompleteGraph(3), true},
+		{graph.Cycle(5), graph.CompleteGraph(2), false},
+		{graph.Cycle(6), graph.CompleteGraph(2), true},
+		{graph.Cycle(7), graph.Cycle(5), true},
+		{graph.Cycle(5), graph.Cycle(7), false},
+		{graph.CompleteGraph(4), graph.CompleteGraph(3), false},
+		{graph.NewDense(0, nil), graph.NewDense(0, nil), true},
+		{graph.NewDense(1, nil), graph.NewDense(0, nil), false},
+	}
+	for _, test := range tests {
+		ok, f := graph.IsHColorable(test.g, test.h)
+		if ok != test.expected {
+			t.Errorf("Wrong answer for %v to %v. Found: %v Expected: %v", graph.Graph6Encode(test.g), graph.Graph6Encode(test.h), ok, test.expected)
+		}
+		if ok && !isHomomorphism(test.g, test.h, f) {
+			t.Errorf("%v is not a homomorphism", f)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 100; trial++ {
+		g := randomGraph(1+r.Intn(8), 0.4, r)
+		h := randomGraph(1+r.Intn(4), 0.6, r)
+		ok, f := graph.IsHColorable(g, h)
+		if ok != (bruteForceHomomorphisms(g, h) > 0) {
+			t.Fatalf("Wrong answer for %v to %v. Found: %v", graph.Graph6Encode(g), graph.Graph6Encode(h), ok)
+		}
+		if ok && !isHomomorphism(g, h, f) {
+			t.Fatalf("%v is not a homomorphism", f)
+		}
+		k := 1 + trial%4
+		okK, _ := graph.IsKColorable(g, k)
+		if okH, _ := graph.IsHColorable(g, graph.CompleteGraph(k)); okH != okK {
+			t.Fatalf("IsHColorable and IsKColorable disagree for %v with k = %v", graph.Graph6Encode(g), k)
+		}
+	}
+}
+
+func isHomomorphism(g, h graph.Graph, f []int) bool {
+	if len(f) != g.N() {
+		return false
+	}
+	for v := 0; v < g.N(); v++ {
+		for _, u := range g.Neighbours(v) {
+			if !h.IsEdge(f[u], f[v]) {
+				return false
+			}
+		}
+	}
+	return true
+}
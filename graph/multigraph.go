@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"sort"
+)
+
+//Multigraph is a data structure for representing an undirected graph which may have loops and multiple edges between the same pair of vertices.
+//The edges are stored as a list of their ends so each edge has an index and the edge Ends[i] joins the vertices Ends[i][0] and Ends[i][1].
+//*Multigraph does not implement the graph interface as many of the algorithms for simple graphs don't make sense for multigraphs.
+type Multigraph struct {
+	NumberOfVertices int
+	Ends             [][2]int
+}
+
+//NewMultigraph creates the multigraph on n vertices with the specified edges. If ends is nil, the multigraph has no edges.
+func NewMultigraph(n int, ends [][2]int) *Multigraph {
+	tmpEnds := make([][2]int, len(ends))
+	for i, e := range ends {
+		if e[0] < 0 || e[0] >= n || e[1] < 0 || e[1] >= n {
+			panic("An edge has an end which is not a vertex.")
+		}
+		tmpEnds[i] = e
+	}
+	return &Multigraph{NumberOfVertices: n, Ends: tmpEnds}
+}
+
+//N returns the number of vertices in g.
+func (g Multigraph) N() int {
+	return g.NumberOfVertices
+}
+
+//M returns the number of edges in g counted with multiplicity.
+func (g Multigraph) M() int {
+	return len(g.Ends)
+}
+
+//Multiplicity returns the number of edges between i and j. If i == j, this is the number of loops at i.
+func (g Multigraph) Multiplicity(i, j int) int {
+	count := 0
+	for _, e := range g.Ends {
+		if (e[0] == i && e[1] == j) || (e[0] == j && e[1] == i) {
+			count++
+		}
+	}
+	return count
+}
+
+//Neighbours returns the neighbours of v in increasing order. A neighbour joined to v by k edges appears k times and v appears twice for each loop at v.
+func (g Multigraph) Neighbours(v int) []int {
+	neighbours := make([]int, 0)
+	for _, e := range g.Ends {
+		if e[0] == v {
+			neighbours = append(neighbours, e[1])
+		}
+		if e[1] == v {
+			neighbours = append(neighbours, e[0])
+		}
+	}
+	sort.Ints(neighbours)
+	return neighbours
+}
+
+//Degrees returns the degree sequence of the multigraph. Each loop contributes 2 to the degree of its vertex.
+func (g Multigraph) Degrees() []int {
+	degrees := make([]int, g.NumberOfVertices)
+	for _, e := range g.Ends {
+		degrees[e[0]]++
+		degrees[e[1]]++
+	}
+	return degrees
+}
+
+//AddEdge modifies the multigraph by adding an edge between i and j. The edge is added even if there is already an edge between i and j and, if i == j, a loop is added.
+func (g *Multigraph) AddEdge(i, j int) {
+	if i < 0 || i >= g.NumberOfVertices || j < 0 || j >= g.NumberOfVertices {
+		panic("An edge has an end which is not a vertex.")
+	}
+	g.Ends = append(g.Ends, [2]int{i, j})
+}
+
+//Underlying returns the simple graph with an edge between i and j if i != j and there is at least one edge between i and j in g.
+func (g Multigraph) Underlying() *SparseGraph {
+	h := NewSparse(g.NumberOfVertices, nil)
+	for _, e := range g.Ends {
+		h.AddEdge(e[0], e[1])
+	}
+	return h
+}
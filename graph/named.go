@@ -0,0 +1,333 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//namedGraphs maps the name of each graph in the catalogue to a function constructing it.
+var namedGraphs = map[string]func() *DenseGraph{
+	"chvatal":           chvatalGraph,
+	"clebsch":           func() *DenseGraph { return FoldedHypercubeGraph(5) },
+	"coxeter":           coxeterGraph,
+	"cube":              func() *DenseGraph { return HypercubeGraph(3) },
+	"desargues":         func() *DenseGraph { return GeneralisedPetersenGraph(10, 3) },
+	"dodecahedron":      func() *DenseGraph { return GeneralisedPetersenGraph(10, 2) },
+	"durer":             func() *DenseGraph { return GeneralisedPetersenGraph(6, 2) },
+	"dyck":              func() *DenseGraph { return lcfGraph([]int{5, -5, 13, -13}, 8) },
+	"franklin":          func() *DenseGraph { return lcfGraph([]int{5, -5}, 6) },
+	"frucht":            func() *DenseGraph { return lcfGraph([]int{-5, -2, -4, 2, 5, -2, 2, 5, -2, -5, 4, 2}, 1) },
+	"grotzsch":          grotzschGraph,
+	"heawood":           func() *DenseGraph { return lcfGraph([]int{5, -5}, 7) },
+	"higman-sims":       higmanSimsGraph,
+	"hoffman-singleton": hoffmanSingletonGraph,
+	"icosahedron":       icosahedronGraph,
+	"mcgee":             func() *DenseGraph { return lcfGraph([]int{12, 7, -7}, 8) },
+	"mobius-kantor":     func() *DenseGraph { return GeneralisedPetersenGraph(8, 3) },
+	"nauru":             func() *DenseGraph { return GeneralisedPetersenGraph(12, 5) },
+	"octahedron":        func() *DenseGraph { return CompletePartiteGraph(2, 2, 2) },
+	"pappus":            func() *DenseGraph { return lcfGraph([]int{5, 7, -7, 7, -7, -5}, 3) },
+	"petersen":          func() *DenseGraph { return GeneralisedPetersenGraph(5, 2) },
+	"schlafli":          schlafliGraph,
+	"shrikhande":        shrikhandeGraph,
+	"tetrahedron":       func() *DenseGraph { return CompleteGraph(4) },
+	"tutte":             tutteGraph,
+	"tutte-coxeter":     func() *DenseGraph { return lcfGraph([]int{-13, -9, 7, -7, 9, 13}, 5) },
+	"wagner":            func() *DenseGraph { return CirculantGraph(8, 1, 4) },
+}
+
+//nameReplacer removes the accents and replaces spaces, underscores and dashes with hyphens.
+var nameReplacer = strings.NewReplacer(" ", "-", "_", "-", "–", "-", "—", "-", "á", "a", "ä", "a", "ö", "o", "ü", "u", "'", "")
+
+//normaliseName converts a name to the form used as a key in namedGraphs by making it lower case and removing a trailing " graph" before using nameReplacer.
+func normaliseName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.TrimSuffix(name, " graph")
+	return nameReplacer.Replace(name)
+}
+
+//NamedGraph returns a new copy of the well-known graph with the given name. The names are case insensitive and accents, spaces and dashes don't matter so "Hoffman–Singleton", "hoffman singleton" and "Hoffman-Singleton graph" all give the same graph. An error is returned if the name isn't in the catalogue.
+//The catalogue contains the Platonic solids (tetrahedron, cube, octahedron, dodecahedron and icosahedron) and the Chvátal, Clebsch, Coxeter, Desargues, Dürer, Dyck, Franklin, Frucht, Grötzsch, Heawood, Higman–Sims, Hoffman–Singleton, McGee, Möbius–Kantor, Nauru, Pappus, Petersen, Schläfli, Shrikhande, Tutte, Tutte–Coxeter and Wagner graphs. NamedGraphNames lists the names.
+func NamedGraph(name string) (*DenseGraph, error) {
+	f, ok := namedGraphs[normaliseName(name)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown graph: %v", name)
+	}
+	return f(), nil
+}
+
+//NamedGraphNames returns the names of the graphs in the catalogue of NamedGraph in alphabetical order.
+func NamedGraphNames() []string {
+	names := make([]string, 0, len(namedGraphs))
+	for name := range namedGraphs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+//lcfGraph returns the cubic Hamiltonian graph with LCF notation [shifts]^repeats i.e. the cycle on len(shifts) * repeats vertices where the vertex i is also joined to i + shifts[i mod len(shifts)].
+func lcfGraph(shifts []int, repeats int) *DenseGraph {
+	n := len(shifts) * repeats
+	g := Cycle(n)
+	for i := 0; i < n; i++ {
+		g.AddEdge(i, ((i+shifts[i%len(shifts)])%n+n)%n)
+	}
+	return g
+}
+
+//graphFromAdjacency returns the graph on n vertices where i is joined to each vertex in adj[i].
+func graphFromAdjacency(n int, adj map[int][]int) *DenseGraph {
+	g := NewDense(n, nil)
+	for i, neighbours := range adj {
+		for _, j := range neighbours {
+			g.AddEdge(i, j)
+		}
+	}
+	return g
+}
+
+//chvatalGraph returns the Chvátal graph which is the smallest triangle-free 4-regular graph with chromatic number 4.
+func chvatalGraph() *DenseGraph {
+	return graphFromAdjacency(12, map[int][]int{
+		0: {1, 4, 6, 9}, 1: {2, 5, 7}, 2: {3, 6, 8}, 3: {4, 7, 9}, 4: {5, 8},
+		5: {10, 11}, 6: {10, 11}, 7: {8, 11}, 8: {10}, 9: {10, 11},
+	})
+}
+
+//coxeterGraph returns the Coxeter graph. The vertices are a_i, b_i, c_i and d_i for i in Z_7 where a_i is joined to b_i, c_i and d_i, and b_i, c_i and d_i are joined to b_{i+1}, c_{i+2} and d_{i+3} respectively.
+func coxeterGraph() *DenseGraph {
+	g := NewDense(28, nil)
+	for i := 0; i < 7; i++ {
+		for k := 1; k <= 3; k++ {
+			g.AddEdge(i, 7*k+i)
+			g.AddEdge(7*k+i, 7*k+(i+k)%7)
+		}
+	}
+	return g
+}
+
+//grotzschGraph returns the Grötzsch graph which is the Mycielskian of the 5-cycle.
+func grotzschGraph() *DenseGraph {
+	g := NewDense(11, nil)
+	for i := 0; i < 5; i++ {
+		j := (i + 1) % 5
+		g.AddEdge(i, j)
+		g.AddEdge(i, 5+j)
+		g.AddEdge(5+i, j)
+		g.AddEdge(5+i, 10)
+	}
+	return g
+}
+
+//hoffmanSingletonGraph returns the Hoffman–Singleton graph using Robertson's construction from 5 pentagons P_h and 5 pentagrams Q_i where the vertex j of P_h is joined to the vertex hi + j of Q_i.
+func hoffmanSingletonGraph() *DenseGraph {
+	g := NewDense(50, nil)
+	//The vertex j of P_h is 5h + j and the vertex j of Q_i is 25 + 5i + j.
+	for h := 0; h < 5; h++ {
+		for j := 0; j < 5; j++ {
+			g.AddEdge(5*h+j, 5*h+(j+1)%5)
+			g.AddEdge(25+5*h+j, 25+5*h+(j+2)%5)
+			for i := 0; i < 5; i++ {
+				g.AddEdge(5*h+j, 25+5*i+(h*i+j)%5)
+			}
+		}
+	}
+	return g
+}
+
+//icosahedronGraph returns the graph of the icosahedron. The vertex 0 is joined to the pentagon 1, ..., 5, the vertex 11 is joined to the pentagon 6, ..., 10 and the two pentagons are joined by a band of triangles.
+func icosahedronGraph() *DenseGraph {
+	g := NewDense(12, nil)
+	for i := 0; i < 5; i++ {
+		j := (i + 1) % 5
+		g.AddEdge(0, 1+i)
+		g.AddEdge(11, 6+i)
+		g.AddEdge(1+i, 1+j)
+		g.AddEdge(6+i, 6+j)
+		g.AddEdge(1+i, 6+i)
+		g.AddEdge(1+i, 6+j)
+	}
+	return g
+}
+
+//schlafliGraph returns the Schläfli graph whose vertices are the 27 lines on a cubic surface and two lines are adjacent if they are skew.
+//The lines are a_i, b_i for i = 0, ..., 5 and c_ij for i < j. The line a_i meets b_j for i != j, a_i and b_i meet c_jk if i is j or k, and c_ij meets c_kl if {i, j} and {k, l} are disjoint.
+func schlafliGraph() *DenseGraph {
+	pairs := make([][2]int, 0, 15)
+	for j := 0; j < 6; j++ {
+		for i := 0; i < j; i++ {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	meets := func(u, v int) bool {
+		if u > v {
+			u, v = v, u
+		}
+		switch {
+		case v < 6:
+			return false
+		case v < 12:
+			if u < 6 {
+				return u != v-6
+			}
+			return false
+		}
+		c := pairs[v-12]
+		if u < 12 {
+			return u%6 == c[0] || u%6 == c[1]
+		}
+		d := pairs[u-12]
+		return c[0] != d[0] && c[0] != d[1] && c[1] != d[0] && c[1] != d[1]
+	}
+	g := NewDense(27, nil)
+	for v := 0; v < 27; v++ {
+		for u := 0; u < v; u++ {
+			if !meets(u, v) {
+				g.AddEdge(u, v)
+			}
+		}
+	}
+	return g
+}
+
+//shrikhandeGraph returns the Shrikhande graph which is the Cayley graph of Z_4 x Z_4 with connection set {±(1, 0), ±(0, 1), ±(1, 1)}.
+func shrikhandeGraph() *DenseGraph {
+	g := NewDense(16, nil)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			for _, d := range [][2]int{{1, 0}, {0, 1}, {1, 1}} {
+				g.AddEdge(4*x+y, 4*((x+d[0])%4)+(y+d[1])%4)
+			}
+		}
+	}
+	return g
+}
+
+//tutteGraph returns the Tutte graph which is a 3-connected cubic planar graph without a Hamiltonian cycle.
+func tutteGraph() *DenseGraph {
+	return graphFromAdjacency(46, map[int][]int{
+		0: {1, 2, 3}, 1: {4, 26}, 2: {10, 11}, 3: {18, 19}, 4: {5, 33}, 5: {6, 29}, 6: {7, 27}, 7: {8, 14}, 8: {9, 38}, 9: {10, 37},
+		10: {39}, 11: {12, 39}, 12: {13, 35}, 13: {14, 15}, 14: {34}, 15: {16, 22}, 16: {17, 44}, 17: {18, 43}, 18: {45}, 19: {20, 45},
+		20: {21, 41}, 21: {22, 23}, 22: {40}, 23: {24, 27}, 24: {25, 32}, 25: {26, 31}, 26: {33}, 27: {28}, 28: {29, 32}, 29: {30},
+		30: {31, 33}, 31: {32}, 34: {35, 38}, 35: {36}, 36: {37, 39}, 37: {38}, 40: {41, 44}, 41: {42}, 42: {43, 45}, 43: {44},
+	})
+}
+
+//higmanSimsGraph returns the Higman–Sims graph constructed from the Steiner system S(3, 6, 22). The vertices are a special vertex, the 22 points and the 77 blocks of the Steiner system. The special vertex is joined to every point, each point is joined to the blocks containing it and two blocks are joined if they are disjoint.
+//The Steiner system has the 21 points of the projective plane PG(2, 4) and a point at infinity. The blocks are the lines of the plane with the point at infinity added and one of the three classes of 56 hyperovals, where two hyperovals are in the same class if they can be joined by a sequence of hyperovals meeting in an even number of points.
+func higmanSimsGraph() *DenseGraph {
+	//The elements of GF(4) are 0, 1, w = 2 and w^2 = 3. Addition is XOR.
+	mul := func(a, b int) int {
+		if a == 0 || b == 0 {
+			return 0
+		}
+		logs := [4]int{0, 0, 1, 2}
+		return [3]int{1, 2, 3}[(logs[a]+logs[b])%3]
+	}
+	dot := func(x, y [3]int) int {
+		return mul(x[0], y[0]) ^ mul(x[1], y[1]) ^ mul(x[2], y[2])
+	}
+	//The points (and lines) of PG(2, 4) are the non-zero vectors whose first non-zero coordinate is 1.
+	points := make([][3]int, 0, 21)
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			for z := 0; z < 4; z++ {
+				v := [3]int{x, y, z}
+				if (x == 1) || (x == 0 && y == 1) || (x == 0 && y == 0 && z == 1) {
+					points = append(points, v)
+				}
+			}
+		}
+	}
+	blocks := make([][]int, 0, 77)
+	lineThrough := make([][]int, 21)
+	for i := range lineThrough {
+		lineThrough[i] = make([]int, 21)
+	}
+	for l, line := range points {
+		block := make([]int, 0, 6)
+		for p, point := range points {
+			if dot(line, point) == 0 {
+				block = append(block, p)
+			}
+		}
+		for _, p := range block {
+			for _, q := range block {
+				lineThrough[p][q] = l
+			}
+		}
+		blocks = append(blocks, append(block, 21))
+	}
+
+	//Find the hyperovals i.e. the sets of 6 points with no 3 collinear.
+	hyperovals := make([][]int, 0, 168)
+	var extend func(current []int, next int)
+	extend = func(current []int, next int) {
+		if len(current) == 6 {
+			hyperovals = append(hyperovals, append([]int(nil), current...))
+			return
+		}
+		for p := next; p < 21; p++ {
+			ok := true
+			for i, q := range current {
+				for _, r := range current[:i] {
+					if lineThrough[q][r] == lineThrough[q][p] {
+						ok = false
+					}
+				}
+			}
+			if ok {
+				extend(append(current, p), p+1)
+			}
+		}
+	}
+	extend(make([]int, 0, 6), 0)
+	intersection := func(a, b []int) int {
+		count := 0
+		for _, x := range a {
+			for _, y := range b {
+				if x == y {
+					count++
+				}
+			}
+		}
+		return count
+	}
+	inClass := make([]bool, len(hyperovals))
+	inClass[0] = true
+	toCheck := []int{0}
+	for len(toCheck) > 0 {
+		i := toCheck[len(toCheck)-1]
+		toCheck = toCheck[:len(toCheck)-1]
+		for j := range hyperovals {
+			if !inClass[j] && intersection(hyperovals[i], hyperovals[j])%2 == 0 {
+				inClass[j] = true
+				toCheck = append(toCheck, j)
+			}
+		}
+	}
+	for i, h := range hyperovals {
+		if inClass[i] {
+			blocks = append(blocks, h)
+		}
+	}
+
+	//The special vertex is 0, the point p is 1 + p and the block b is 23 + b.
+	g := NewDense(100, nil)
+	for p := 0; p < 22; p++ {
+		g.AddEdge(0, 1+p)
+	}
+	for b, block := range blocks {
+		for _, p := range block {
+			g.AddEdge(1+p, 23+b)
+		}
+		for c := 0; c < b; c++ {
+			if intersection(block, blocks[c]) == 0 {
+				g.AddEdge(23+c, 23+b)
+			}
+		}
+	}
+	return g
+}
@@ -0,0 +1,119 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+func TestNamedGraph(t *testing.T) {
+	//A value of -1 means the invariant isn't checked because it is too slow to compute. The degree is -1 for graphs which aren't regular.
+	tests := []struct {
+		name                                     string
+		n, m, degree, girth, diameter, chromatic int
+		planar                                   bool
+		automorphisms                            int
+	}{
+		{"Chvátal", 12, 24, 4, 4, 2, 4, false, 8},
+		{"Clebsch", 16, 40, 5, 4, 2, 4, false, 1920},
+		{"Coxeter", 28, 42, 3, 7, 4, 3, false, 336},
+		{"Cube", 8, 12, 3, 4, 3, 2, true, 48},
+		{"Desargues", 20, 30, 3, 6, 5, 2, false, 240},
+		{"Dodecahedron", 20, 30, 3, 5, 5, 3, true, 120},
+		{"Dürer", 12, 18, 3, 3, 4, 3, true, 12},
+		{"Dyck", 32, 48, 3, 6, 5, 2, false, 192},
+		{"Franklin", 12, 18, 3, 4, 3, 2, false, 48},
+		{"Frucht", 12, 18, 3, 3, 4, 3, true, 1},
+		{"Grötzsch", 11, 20, -1, 4, 2, 4, false, 10},
+		{"Heawood", 14, 21, 3, 6, 3, 2, false, 336},
+		{"Higman–Sims", 100, 1100, 22, 4, 2, -1, false, -1},
+		{"Hoffman–Singleton", 50, 175, 7, 5, 2, -1, false, -1},
+		{"Icosahedron", 12, 30, 5, 3, 3, 4, true, 120},
+		{"McGee", 24, 36, 3, 7, 4, 3, false, 32},
+		{"Möbius–Kantor", 16, 24, 3, 6, 4, 2, false, 96},
+		{"Nauru", 24, 36, 3, 6, 4, 2, false, 144},
+		{"Octahedron", 6, 12, 4, 3, 2, 3, true, 48},
+		{"Pappus", 18, 27, 3, 6, 4, 2, false, 216},
+		{"Petersen", 10, 15, 3, 5, 2, 3, false, 120},
+		{"Schläfli", 27, 216, 16, 3, 2, -1, false, -1},
+		{"Shrikhande", 16, 48, 6, 3, 2, 4, false, 192},
+		{"Tetrahedron", 4, 6, 3, 3, 1, 4, true, 24},
+		{"Tutte", 46, 69, 3, 4, 8, 3, true, 3},
+		{"Tutte–Coxeter", 30, 45, 3, 8, 4, 2, false, 1440},
+		{"Wagner", 8, 12, 3, 4, 2, 3, false, 16},
+	}
+	if len(tests) != len(graph.NamedGraphNames()) {
+		t.Errorf("Not every named graph is tested")
+	}
+	for _, test := range tests {
+		g, err := graph.NamedGraph(test.name)
+		if err != nil {
+			t.Errorf("%v", err)
+			continue
+		}
+		if g.N() != test.n || g.M() != test.m {
+			t.Errorf("Wrong size for %v. Found: (%v, %v) Expected: (%v, %v)", test.name, g.N(), g.M(), test.n, test.m)
+			continue
+		}
+		if test.degree != -1 && (graph.MinDegree(g) != test.degree || graph.MaxDegree(g) != test.degree) {
+			t.Errorf("%v is not %v-regular", test.name, test.degree)
+		}
+		if girth := graph.Girth(g); girth != test.girth {
+			t.Errorf("Wrong girth for %v. Found: %v Expected: %v", test.name, girth, test.girth)
+		}
+		if diameter := graph.Diameter(g); diameter != test.diameter {
+			t.Errorf("Wrong diameter for %v. Found: %v Expected: %v", test.name, diameter, test.diameter)
+		}
+		if test.chromatic != -1 {
+			if chromatic, _ := graph.ChromaticNumber(g); chromatic != test.chromatic {
+				t.Errorf("Wrong chromatic number for %v. Found: %v Expected: %v", test.name, chromatic, test.chromatic)
+			}
+		}
+		if graph.IsPlanar(g) != test.planar {
+			t.Errorf("Wrong planarity for %v. Found: %v Expected: %v", test.name, !test.planar, test.planar)
+		}
+		if test.automorphisms != -1 {
+			if automorphisms := graph.CountEmbeddings(g, g, nil); automorphisms != test.automorphisms {
+				t.Errorf("Wrong number of automorphisms for %v. Found: %v Expected: %v", test.name, automorphisms, test.automorphisms)
+			}
+		}
+	}
+
+	//Check the parameters of the strongly regular graphs.
+	srgs := []struct {
+		name       string
+		lambda, mu int
+	}{
+		{"Clebsch", 0, 2},
+		{"Higman-Sims", 0, 6},
+		{"Hoffman-Singleton", 0, 1},
+		{"Petersen", 0, 1},
+		{"Schlafli", 10, 8},
+		{"Shrikhande", 2, 2},
+	}
+	for _, test := range srgs {
+		g, _ := graph.NamedGraph(test.name)
+		for v := 0; v < g.N(); v++ {
+			for u := 0; u < v; u++ {
+				common := 0
+				for w := 0; w < g.N(); w++ {
+					if g.IsEdge(u, w) && g.IsEdge(v, w) {
+						common++
+					}
+				}
+				if (g.IsEdge(u, v) && common != test.lambda) || (!g.IsEdge(u, v) && common != test.mu) {
+					t.Fatalf("%v is not strongly regular with lambda = %v and mu = %v", test.name, test.lambda, test.mu)
+				}
+			}
+		}
+	}
+
+	for _, name := range []string{"hoffman singleton graph", "HIGMAN_SIMS", "Tutte-Coxeter graph", "mobius kantor"} {
+		if _, err := graph.NamedGraph(name); err != nil {
+			t.Errorf("%v", err)
+		}
+	}
+	if _, err := graph.NamedGraph("Not a graph"); err == nil {
+		t.Errorf("Expected an error for an unknown name")
+	}
+}
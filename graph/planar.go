@@ -7,10 +7,16 @@ import (
 	"github.com/Tom-Johnston/mamba/sortints"
 )
 
-//IsPlanar returns true if the graph is a planar graph and false otherwise. The current implementation is simple but runs in O(n^2) while linear time algorithms are known.
-//TODO Which algorithm does this use?
-//TODO Can we make it output a planar embedding?
+//IsPlanar returns true if the graph is a planar graph and false otherwise.
+//This uses the left-right planarity test and runs in O(n + m) time. It only uses the neighbourhoods of the vertices so it works well for large sparse graphs stored as a *SparseGraph.
+//Use PlanarEmbedding to find a planar embedding or a certificate that the graph is not planar.
 func IsPlanar(g Graph) bool {
+	return newLRPlanarity(g).isPlanar()
+}
+
+//isPlanarDMP returns true if the graph is a planar graph and false otherwise.
+//This is the original implementation of IsPlanar and is based on the algorithm of Demoucron, Malgrange and Pertuiset which embeds H-fragments one path at a time. It runs in O(n^2) time and is kept for cross-checking the linear time implementation in the tests.
+func isPlanarDMP(g Graph) bool {
 	if g.N() < 5 {
 		return true
 	}
@@ -0,0 +1,615 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//This file contains an implementation of the left-right planarity test from U. Brandes, The Left-Right Planarity Test (2009), available here: https://citeseerx.ist.psu.edu/viewdoc/summary?doi=10.1.1.217.9208.
+//The structure of the implementation follows the description in the paper and the implementation in NetworkX but the recursive depth-first searches are replaced with iterative versions so there is no problem with very deep searches.
+//The edges are oriented by the first DFS and are referred to as arcs. The arc a is oriented from tail[a] to head[a].
+//In the embedding, each arc a has two half-edges: 2a leaves tail[a] and 2a + 1 leaves head[a].
+
+//lrInterval is an interval of return edges. An empty interval has low == high == -1.
+type lrInterval struct {
+	low, high int
+}
+
+func (i lrInterval) empty() bool {
+	return i.low == -1 && i.high == -1
+}
+
+//lrConflictPair is a pair of intervals of return edges where the return edges in the left interval must be on the other side to the return edges in the right interval.
+type lrConflictPair struct {
+	left, right lrInterval
+}
+
+func newConflictPair() *lrConflictPair {
+	return &lrConflictPair{left: lrInterval{-1, -1}, right: lrInterval{-1, -1}}
+}
+
+func (p *lrConflictPair) swap() {
+	p.left, p.right = p.right, p.left
+}
+
+//lrPlanarity holds the state of the left-right planarity test.
+type lrPlanarity struct {
+	n   int
+	adj [][]int
+
+	//Orientation
+	tail, head   []int
+	outArcs      [][]int
+	height       []int
+	parentArc    []int
+	lowpt        []int
+	lowpt2       []int
+	nestingDepth []int
+	roots        []int
+
+	//Testing
+	ref         []int
+	side        []int
+	lowptArc    []int
+	stackBottom []*lrConflictPair
+	stack       []*lrConflictPair
+	oldRef      []int
+	signStack   []int
+
+	//Embedding
+	leftRef, rightRef []int
+	cw, ccw           []int
+	first             []int
+}
+
+//newLRPlanarity prepares the left-right planarity test for the graph g.
+func newLRPlanarity(g Graph) *lrPlanarity {
+	n := g.N()
+	lr := &lrPlanarity{n: n, adj: make([][]int, n)}
+	for v := range lr.adj {
+		lr.adj[v] = g.Neighbours(v)
+		if !sort.IntsAreSorted(lr.adj[v]) {
+			sort.Ints(lr.adj[v])
+		}
+	}
+	return lr
+}
+
+//isPlanar runs the orientation and testing phases and returns true if the graph is planar.
+func (lr *lrPlanarity) isPlanar() bool {
+	n := lr.n
+	m := 0
+	for _, nbrs := range lr.adj {
+		m += len(nbrs)
+	}
+	m /= 2
+	if n > 2 && m > 3*n-6 {
+		return false
+	}
+	lr.orient(m)
+	lr.sortArcs()
+	for _, r := range lr.roots {
+		if !lr.test(r) {
+			return false
+		}
+	}
+	return true
+}
+
+//embedding returns a rotation system for the graph. It must only be called after isPlanar has returned true.
+func (lr *lrPlanarity) embedding() [][]int {
+	m := len(lr.tail)
+	for a := 0; a < m; a++ {
+		lr.nestingDepth[a] *= lr.sign(a)
+	}
+	lr.sortArcs()
+
+	lr.cw = make([]int, 2*m)
+	lr.ccw = make([]int, 2*m)
+	lr.first = make([]int, lr.n)
+	lr.leftRef = make([]int, lr.n)
+	lr.rightRef = make([]int, lr.n)
+	for v := range lr.first {
+		lr.first[v] = -1
+		prev := -1
+		for _, a := range lr.outArcs[v] {
+			lr.addHalfEdgeCW(v, 2*a, prev)
+			prev = 2 * a
+		}
+	}
+	for _, r := range lr.roots {
+		lr.embed(r)
+	}
+
+	rotation := make([][]int, lr.n)
+	for v := range rotation {
+		rotation[v] = make([]int, 0, len(lr.adj[v]))
+		if lr.first[v] == -1 {
+			continue
+		}
+		x := lr.first[v]
+		for {
+			rotation[v] = append(rotation[v], lr.otherEnd(x))
+			x = lr.cw[x]
+			if x == lr.first[v] {
+				break
+			}
+		}
+	}
+	return rotation
+}
+
+//orient orients the edges of the graph using a DFS and calculates the lowpoints and nesting depths of the arcs.
+func (lr *lrPlanarity) orient(m int) {
+	n := lr.n
+	lr.tail = make([]int, 0, m)
+	lr.head = make([]int, 0, m)
+	lr.outArcs = make([][]int, n)
+	lr.height = make([]int, n)
+	lr.parentArc = make([]int, n)
+	lr.lowpt = make([]int, 0, m)
+	lr.lowpt2 = make([]int, 0, m)
+	lr.nestingDepth = make([]int, 0, m)
+	for v := 0; v < n; v++ {
+		lr.height[v] = -1
+		lr.parentArc[v] = -1
+	}
+
+	//Label each edge so we can tell if it has already been oriented. Since the neighbourhoods are sorted, the edge vw with w < v is the next unlabelled edge of w.
+	edgeIDs := make([][]int, n)
+	next := make([]int, n)
+	id := 0
+	for v := 0; v < n; v++ {
+		edgeIDs[v] = make([]int, len(lr.adj[v]))
+		for i, w := range lr.adj[v] {
+			if w < v {
+				for lr.adj[w][next[w]] < w {
+					next[w]++
+				}
+				edgeIDs[v][i] = edgeIDs[w][next[w]]
+				next[w]++
+			} else {
+				edgeIDs[v][i] = id
+				id++
+			}
+		}
+	}
+	oriented := make([]bool, id)
+
+	type frame struct {
+		v, i, childArc int
+	}
+	stack := make([]frame, 0, n)
+	for s := 0; s < n; s++ {
+		if lr.height[s] != -1 {
+			continue
+		}
+		lr.height[s] = 0
+		lr.roots = append(lr.roots, s)
+		stack = append(stack, frame{v: s, childArc: -1})
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			v := f.v
+			if f.childArc != -1 {
+				lr.finishArc(v, f.childArc)
+				f.childArc = -1
+				f.i++
+				continue
+			}
+			if f.i == len(lr.adj[v]) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			w := lr.adj[v][f.i]
+			if oriented[edgeIDs[v][f.i]] {
+				f.i++
+				continue
+			}
+			oriented[edgeIDs[v][f.i]] = true
+			a := len(lr.tail)
+			lr.tail = append(lr.tail, v)
+			lr.head = append(lr.head, w)
+			lr.outArcs[v] = append(lr.outArcs[v], a)
+			lr.lowpt = append(lr.lowpt, lr.height[v])
+			lr.lowpt2 = append(lr.lowpt2, lr.height[v])
+			lr.nestingDepth = append(lr.nestingDepth, 0)
+			if lr.height[w] == -1 {
+				//Tree edge
+				lr.parentArc[w] = a
+				lr.height[w] = lr.height[v] + 1
+				f.childArc = a
+				stack = append(stack, frame{v: w, childArc: -1})
+				continue
+			}
+			//Back edge
+			lr.lowpt[a] = lr.height[w]
+			lr.finishArc(v, a)
+			f.i++
+		}
+	}
+}
+
+//finishArc calculates the nesting depth of the arc a leaving v and updates the lowpoints of the parent arc of v.
+func (lr *lrPlanarity) finishArc(v, a int) {
+	lr.nestingDepth[a] = 2 * lr.lowpt[a]
+	if lr.lowpt2[a] < lr.height[v] {
+		//Chordal
+		lr.nestingDepth[a]++
+	}
+	e := lr.parentArc[v]
+	if e == -1 {
+		return
+	}
+	if lr.lowpt[a] < lr.lowpt[e] {
+		lr.lowpt2[e] = min(lr.lowpt[e], lr.lowpt2[a])
+		lr.lowpt[e] = lr.lowpt[a]
+	} else if lr.lowpt[a] > lr.lowpt[e] {
+		lr.lowpt2[e] = min(lr.lowpt2[e], lr.lowpt[a])
+	} else {
+		lr.lowpt2[e] = min(lr.lowpt2[e], lr.lowpt2[a])
+	}
+}
+
+//sortArcs sorts the arcs leaving each vertex by their nesting depth using a counting sort.
+func (lr *lrPlanarity) sortArcs() {
+	//The nesting depths are in [-2n - 1, 2n + 1].
+	offset := 2*lr.n + 1
+	counts := make([]int, 2*offset+2)
+	for _, d := range lr.nestingDepth {
+		counts[d+offset+1]++
+	}
+	for i := 1; i < len(counts); i++ {
+		counts[i] += counts[i-1]
+	}
+	sorted := make([]int, len(lr.nestingDepth))
+	for a, d := range lr.nestingDepth {
+		sorted[counts[d+offset]] = a
+		counts[d+offset]++
+	}
+	for v := range lr.outArcs {
+		lr.outArcs[v] = lr.outArcs[v][:0]
+	}
+	for _, a := range sorted {
+		lr.outArcs[lr.tail[a]] = append(lr.outArcs[lr.tail[a]], a)
+	}
+}
+
+func (lr *lrPlanarity) top() *lrConflictPair {
+	if len(lr.stack) == 0 {
+		return nil
+	}
+	return lr.stack[len(lr.stack)-1]
+}
+
+func (lr *lrPlanarity) pop() *lrConflictPair {
+	p := lr.stack[len(lr.stack)-1]
+	lr.stack = lr.stack[:len(lr.stack)-1]
+	return p
+}
+
+//conflicting returns true if the interval i contains a return edge which has a higher lowpoint than the arc b.
+func (lr *lrPlanarity) conflicting(i lrInterval, b int) bool {
+	return !i.empty() && lr.lowpt[i.high] > lr.lowpt[b]
+}
+
+//lowest returns the lowest lowpoint of a return edge in the conflict pair.
+func (lr *lrPlanarity) lowest(p *lrConflictPair) int {
+	if p.left.empty() {
+		return lr.lowpt[p.right.low]
+	}
+	if p.right.empty() {
+		return lr.lowpt[p.left.low]
+	}
+	return min(lr.lowpt[p.left.low], lr.lowpt[p.right.low])
+}
+
+//test runs the testing phase on the DFS tree with root r and returns false if the graph is not planar.
+func (lr *lrPlanarity) test(r int) bool {
+	m := len(lr.tail)
+	if lr.ref == nil {
+		lr.ref = make([]int, m)
+		lr.side = make([]int, m)
+		lr.lowptArc = make([]int, m)
+		lr.stackBottom = make([]*lrConflictPair, m)
+		for a := 0; a < m; a++ {
+			lr.ref[a] = -1
+			lr.side[a] = 1
+		}
+	}
+
+	type frame struct {
+		v, i, childArc int
+	}
+	stack := []frame{{v: r, childArc: -1}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		v := f.v
+		e := lr.parentArc[v]
+		var ei int
+		if f.childArc != -1 {
+			ei = f.childArc
+			f.childArc = -1
+		} else {
+			if f.i == len(lr.outArcs[v]) {
+				stack = stack[:len(stack)-1]
+				if e != -1 {
+					lr.removeBackEdges(e)
+				}
+				continue
+			}
+			ei = lr.outArcs[v][f.i]
+			w := lr.head[ei]
+			lr.stackBottom[ei] = lr.top()
+			if ei == lr.parentArc[w] {
+				//Tree edge
+				f.childArc = ei
+				stack = append(stack, frame{v: w, childArc: -1})
+				continue
+			}
+			//Back edge
+			lr.lowptArc[ei] = ei
+			p := newConflictPair()
+			p.right = lrInterval{ei, ei}
+			lr.stack = append(lr.stack, p)
+		}
+
+		//Integrate the new return edges.
+		if lr.lowpt[ei] < lr.height[v] {
+			if ei == lr.outArcs[v][0] {
+				lr.lowptArc[e] = lr.lowptArc[ei]
+			} else if !lr.addConstraints(ei, e) {
+				return false
+			}
+		}
+		f.i++
+	}
+	return true
+}
+
+//addConstraints adds the constraints from the arc ei leaving v where e is the parent arc of v. It returns false if the constraints can't be satisfied.
+func (lr *lrPlanarity) addConstraints(ei, e int) bool {
+	p := newConflictPair()
+	//Merge the return edges of ei into p.right.
+	for {
+		q := lr.pop()
+		if !q.left.empty() {
+			q.swap()
+		}
+		if !q.left.empty() {
+			return false
+		}
+		if lr.lowpt[q.right.low] > lr.lowpt[e] {
+			//Merge the intervals
+			if p.right.empty() {
+				p.right = q.right
+			} else {
+				lr.ref[p.right.low] = q.right.high
+			}
+			p.right.low = q.right.low
+		} else {
+			//Align
+			lr.ref[q.right.low] = lr.lowptArc[e]
+		}
+		if lr.top() == lr.stackBottom[ei] {
+			break
+		}
+	}
+
+	//Merge the conflicting return edges of the earlier arcs into p.left.
+	for len(lr.stack) > 0 && (lr.conflicting(lr.top().left, ei) || lr.conflicting(lr.top().right, ei)) {
+		q := lr.pop()
+		if lr.conflicting(q.right, ei) {
+			q.swap()
+		}
+		if lr.conflicting(q.right, ei) {
+			return false
+		}
+		//Merge the interval below lowpt(ei) into p.right.
+		if p.right.low != -1 {
+			lr.ref[p.right.low] = q.right.high
+		}
+		if q.right.low != -1 {
+			p.right.low = q.right.low
+		}
+		if p.left.empty() {
+			p.left = q.left
+		} else {
+			lr.ref[p.left.low] = q.left.high
+		}
+		p.left.low = q.left.low
+	}
+
+	if !(p.left.empty() && p.right.empty()) {
+		lr.stack = append(lr.stack, p)
+	}
+	return true
+}
+
+//removeBackEdges removes the back edges ending at the parent of e which are no longer needed.
+func (lr *lrPlanarity) removeBackEdges(e int) {
+	u := lr.tail[e]
+	//Drop entire conflict pairs.
+	for len(lr.stack) > 0 && lr.lowest(lr.top()) == lr.height[u] {
+		p := lr.pop()
+		if p.left.low != -1 {
+			lr.side[p.left.low] = -1
+		}
+	}
+
+	if len(lr.stack) > 0 {
+		//One more conflict pair to consider.
+		p := lr.pop()
+		//Trim the left interval.
+		for p.left.high != -1 && lr.head[p.left.high] == u {
+			p.left.high = lr.ref[p.left.high]
+		}
+		if p.left.high == -1 && p.left.low != -1 {
+			//Just emptied
+			lr.ref[p.left.low] = p.right.low
+			lr.side[p.left.low] = -1
+			p.left.low = -1
+		}
+		//Trim the right interval.
+		for p.right.high != -1 && lr.head[p.right.high] == u {
+			p.right.high = lr.ref[p.right.high]
+		}
+		if p.right.high == -1 && p.right.low != -1 {
+			//Just emptied
+			lr.ref[p.right.low] = p.left.low
+			lr.side[p.right.low] = -1
+			p.right.low = -1
+		}
+		lr.stack = append(lr.stack, p)
+	}
+
+	//The side of e is the side of a highest return edge.
+	if lr.lowpt[e] < lr.height[u] && len(lr.stack) > 0 {
+		hl := lr.top().left.high
+		hr := lr.top().right.high
+		if hl != -1 && (hr == -1 || lr.lowpt[hl] > lr.lowpt[hr]) {
+			lr.ref[e] = hl
+		} else {
+			lr.ref[e] = hr
+		}
+	}
+}
+
+//sign returns the final side of the arc a by following the chain of references.
+func (lr *lrPlanarity) sign(a int) int {
+	if lr.oldRef == nil {
+		lr.oldRef = make([]int, len(lr.tail))
+		for e := range lr.oldRef {
+			lr.oldRef[e] = -1
+		}
+	}
+	stack := append(lr.signStack[:0], a)
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if lr.ref[e] != -1 {
+			stack = append(stack, e, lr.ref[e])
+			lr.oldRef[e] = lr.ref[e]
+			lr.ref[e] = -1
+		} else if r := lr.oldRef[e]; r != -1 {
+			lr.side[e] *= lr.side[r]
+			lr.oldRef[e] = -1
+		}
+	}
+	lr.signStack = stack
+	return lr.side[a]
+}
+
+//otherEnd returns the vertex which the half-edge x points to.
+func (lr *lrPlanarity) otherEnd(x int) int {
+	if x%2 == 0 {
+		return lr.head[x/2]
+	}
+	return lr.tail[x/2]
+}
+
+//addHalfEdgeCW adds the half-edge x at v immediately clockwise of the half-edge ref. If ref is -1, v must not have any half-edges.
+func (lr *lrPlanarity) addHalfEdgeCW(v, x, ref int) {
+	if ref == -1 {
+		lr.first[v] = x
+		lr.cw[x] = x
+		lr.ccw[x] = x
+		return
+	}
+	next := lr.cw[ref]
+	lr.cw[ref] = x
+	lr.ccw[x] = ref
+	lr.cw[x] = next
+	lr.ccw[next] = x
+}
+
+//addHalfEdgeCCW adds the half-edge x at v immediately counter-clockwise of the half-edge ref. If ref is -1, v must not have any half-edges.
+func (lr *lrPlanarity) addHalfEdgeCCW(v, x, ref int) {
+	if ref == -1 {
+		lr.addHalfEdgeCW(v, x, -1)
+		return
+	}
+	lr.addHalfEdgeCW(v, x, lr.ccw[ref])
+	if ref == lr.first[v] {
+		lr.first[v] = x
+	}
+}
+
+//embed adds the half-edges entering the vertices of the DFS tree with root r to the rotation system.
+func (lr *lrPlanarity) embed(r int) {
+	type frame struct {
+		v, i int
+	}
+	stack := []frame{{v: r}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		v := f.v
+		if f.i == len(lr.outArcs[v]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		ei := lr.outArcs[v][f.i]
+		f.i++
+		w := lr.head[ei]
+		if ei == lr.parentArc[w] {
+			//Tree edge
+			lr.addHalfEdgeCCW(w, 2*ei+1, lr.first[w])
+			lr.leftRef[v] = 2 * ei
+			lr.rightRef[v] = 2 * ei
+			stack = append(stack, frame{v: w})
+			continue
+		}
+		//Back edge
+		if lr.side[ei] == 1 {
+			lr.addHalfEdgeCW(w, 2*ei+1, lr.rightRef[w])
+		} else {
+			lr.addHalfEdgeCCW(w, 2*ei+1, lr.leftRef[w])
+			lr.leftRef[w] = 2*ei + 1
+		}
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+//PlanarEmbedding returns true and a planar embedding of g if g is planar, else it returns false and a Kuratowski subgraph of g which certifies that g is not planar.
+//The embedding is given as a rotation system: rotation[v] lists the neighbours of v in clockwise order around v. The faces of the embedding can be found by repeatedly moving from the half-edge (u, v) to the half-edge (v, w) where w is the neighbour before u in rotation[v].
+//The Kuratowski subgraph has the same vertices as g and its edges form a subdivision of K_5 or K_{3, 3}. It is found by deleting the edges of g one at a time and keeping an edge only if deleting it would make the graph planar.
+//The planarity test and the embedding use the left-right planarity test and run in O(n + m) time. Finding a Kuratowski subgraph takes O(n^2) time since only the first 3n - 5 edges are needed.
+func PlanarEmbedding(g Graph) (ok bool, rotation [][]int, kuratowski *SparseGraph) {
+	lr := newLRPlanarity(g)
+	if lr.isPlanar() {
+		return true, lr.embedding(), nil
+	}
+
+	//A planar graph has at most 3n - 6 edges so the first 3n - 5 edges are still not planar.
+	n := g.N()
+	edges := make([][2]int, 0, min(g.M(), 3*n-5))
+	neighbourhoods := make([]sortints.SortedInts, n)
+edgeLoop:
+	for v := 0; v < n; v++ {
+		for _, u := range g.Neighbours(v) {
+			if u > v {
+				continue
+			}
+			if len(edges) == 3*n-5 {
+				break edgeLoop
+			}
+			edges = append(edges, [2]int{u, v})
+			neighbourhoods[u] = append(neighbourhoods[u], v)
+			neighbourhoods[v] = append(neighbourhoods[v], u)
+		}
+	}
+	h := NewSparse(n, neighbourhoods)
+	for _, e := range edges {
+		h.RemoveEdge(e[0], e[1])
+		if newLRPlanarity(h).isPlanar() {
+			h.AddEdge(e[0], e[1])
+		}
+	}
+	return false, nil, h
+}
@@ -1,11 +1,13 @@
 package graph_test
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/Tom-Johnston/mamba/graph"
 	"github.com/Tom-Johnston/mamba/graph/search"
 	"github.com/Tom-Johnston/mamba/ints"
+	"github.com/Tom-Johnston/mamba/sortints"
 )
 
 func TestPlanarGraph(t *testing.T) {
@@ -17,7 +19,11 @@ func TestPlanarGraph(t *testing.T) {
 		iter := search.All(i, 0, 1)
 		for iter.Next() {
 			g := iter.Value()
-			if graph.IsPlanar(g) {
+			planar := graph.IsPlanar(g)
+			if planar != graph.IsPlanarDMP(g) {
+				t.Fatalf("IsPlanar and IsPlanarDMP disagree for %v", graph.Graph6Encode(g))
+			}
+			if planar {
 				foundData[g.N()]++
 			}
 		}
@@ -38,4 +44,204 @@ func TestPlanarGraph(t *testing.T) {
 	if graph.IsPlanar(graph.CompletePartiteGraph(3, 3)) {
 		t.Log("K_{3,3} - Found: true Expected: false")
 	}
+
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		g := randomGraph(10+r.Intn(30), 0.02+0.1*r.Float64(), r)
+		if graph.IsPlanar(g) != graph.IsPlanarDMP(g) {
+			t.Fatalf("IsPlanar and IsPlanarDMP disagree for %v", graph.Graph6Encode(g))
+		}
+	}
+}
+
+//gridGraph returns the a x b grid as a *SparseGraph. If torus is true, the grid wraps around in both directions.
+func gridGraph(a, b int, torus bool) *graph.SparseGraph {
+	g := graph.NewSparse(a*b, nil)
+	for i := 0; i < a; i++ {
+		for j := 0; j < b; j++ {
+			if i+1 < a || torus {
+				g.AddEdge(i*b+j, ((i+1)%a)*b+j)
+			}
+			if j+1 < b || torus {
+				g.AddEdge(i*b+j, i*b+(j+1)%b)
+			}
+		}
+	}
+	return g
+}
+
+func TestIsPlanarLarge(t *testing.T) {
+	if !graph.IsPlanar(gridGraph(500, 400, false)) {
+		t.Error("The 500 x 400 grid - Found: false Expected: true")
+	}
+	if graph.IsPlanar(gridGraph(500, 400, true)) {
+		t.Error("The 500 x 400 torus - Found: true Expected: false")
+	}
+	//A long path has a very deep DFS tree.
+	path := gridGraph(1, 200000, false)
+	ok, rotation, _ := graph.PlanarEmbedding(path)
+	if !ok || !checkRotationSystem(path, rotation) {
+		t.Error("Failed to embed a path on 200000 vertices")
+	}
+	grid := gridGraph(100, 100, false)
+	ok, rotation, _ = graph.PlanarEmbedding(grid)
+	if !ok || !checkRotationSystem(grid, rotation) {
+		t.Error("Failed to embed the 100 x 100 grid")
+	}
+}
+
+//checkRotationSystem checks that rotation is a planar embedding of g using Euler's formula. The faces of each component are counted separately so each component should satisfy n - m + f = 2.
+func checkRotationSystem(g graph.Graph, rotation [][]int) bool {
+	n := g.N()
+	if len(rotation) != n {
+		return false
+	}
+	//position[v][u] is the position of u in rotation[v].
+	position := make([]map[int]int, n)
+	for v := 0; v < n; v++ {
+		if !ints.Equal(sortints.NewSortedInts(rotation[v]...), g.Neighbours(v)) {
+			return false
+		}
+		position[v] = make(map[int]int)
+		for i, u := range rotation[v] {
+			position[v][u] = i
+		}
+	}
+	//Count the faces by following the half-edges.
+	seen := make(map[[2]int]bool)
+	faces := 0
+	for v := 0; v < n; v++ {
+		for _, u := range rotation[v] {
+			if seen[[2]int{v, u}] {
+				continue
+			}
+			faces++
+			a, b := v, u
+			for !seen[[2]int{a, b}] {
+				seen[[2]int{a, b}] = true
+				d := len(rotation[b])
+				a, b = b, rotation[b][(position[b][a]+d-1)%d]
+			}
+		}
+	}
+	//Count the components with a DFS. Each component without edges has one face but isn't counted above.
+	components := 0
+	visited := make([]bool, n)
+	for v := 0; v < n; v++ {
+		if visited[v] {
+			continue
+		}
+		components++
+		if len(rotation[v]) == 0 {
+			faces++
+		}
+		visited[v] = true
+		toCheck := []int{v}
+		for len(toCheck) > 0 {
+			u := toCheck[len(toCheck)-1]
+			toCheck = toCheck[:len(toCheck)-1]
+			for _, w := range rotation[u] {
+				if !visited[w] {
+					visited[w] = true
+					toCheck = append(toCheck, w)
+				}
+			}
+		}
+	}
+	return n-g.M()+faces == 2*components
+}
+
+//isKuratowskiSubgraph checks that k is a minimal non-planar subgraph of g whose edges form a subdivision of K_5 or K_{3, 3}.
+func isKuratowskiSubgraph(g graph.Graph, k *graph.SparseGraph) bool {
+	if k == nil || k.N() != g.N() {
+		return false
+	}
+	degreeCount := make([]int, k.N())
+	for v := 0; v < k.N(); v++ {
+		for _, u := range k.Neighbours(v) {
+			if !g.IsEdge(u, v) {
+				return false
+			}
+		}
+		degreeCount[k.DegreeSequence[v]]++
+	}
+	if degreeCount[1] != 0 || !(degreeCount[4] == 5 && degreeCount[3] == 0 || degreeCount[3] == 6 && degreeCount[4] == 0) {
+		return false
+	}
+	if graph.IsPlanar(k) {
+		return false
+	}
+	for v := 0; v < k.N(); v++ {
+		for _, u := range k.Neighbours(v) {
+			h := graph.NewSparse(k.N(), k.Neighbourhoods)
+			h.RemoveEdge(u, v)
+			if !graph.IsPlanar(h) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestPlanarEmbedding(t *testing.T) {
+	for n := 0; n <= 7; n++ {
+		iter := search.All(n, 0, 1)
+		for iter.Next() {
+			g := iter.Value()
+			ok, rotation, k := graph.PlanarEmbedding(g)
+			if ok != graph.IsPlanarDMP(g) {
+				t.Fatalf("PlanarEmbedding and IsPlanarDMP disagree for %v", graph.Graph6Encode(g))
+			}
+			if ok && !checkRotationSystem(g, rotation) {
+				t.Fatalf("%v is not a planar embedding of %v", rotation, graph.Graph6Encode(g))
+			}
+			if !ok && !isKuratowskiSubgraph(g, k) {
+				t.Fatalf("%v is not a Kuratowski subgraph of %v", graph.Sparse6Encode(k), graph.Graph6Encode(g))
+			}
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		g := randomGraph(10+r.Intn(20), 0.05+0.1*r.Float64(), r)
+		ok, rotation, k := graph.PlanarEmbedding(g)
+		if ok != graph.IsPlanarDMP(g) {
+			t.Fatalf("PlanarEmbedding and IsPlanarDMP disagree for %v", graph.Graph6Encode(g))
+		}
+		if ok && !checkRotationSystem(g, rotation) {
+			t.Fatalf("%v is not a planar embedding of %v", rotation, graph.Graph6Encode(g))
+		}
+		if !ok && !isKuratowskiSubgraph(g, k) {
+			t.Fatalf("%v is not a Kuratowski subgraph of %v", graph.Sparse6Encode(k), graph.Graph6Encode(g))
+		}
+	}
+
+	//Check some named graphs.
+	tests := []struct {
+		g      graph.Graph
+		planar bool
+	}{
+		{graph.HypercubeGraph(3), true},
+		{graph.HypercubeGraph(4), false},
+		{graph.GeneralisedPetersenGraph(5, 2), false},
+		{graph.GeneralisedPetersenGraph(12, 1), true},
+		{graph.RookGraph(3, 3), false},
+		{graph.FriendshipGraph(20), true},
+		{graph.CompletePartiteGraph(2, 20), true},
+		{graph.CompletePartiteGraph(3, 3, 3), false},
+		{graph.CompleteGraph(30), false},
+	}
+	for i, test := range tests {
+		ok, rotation, k := graph.PlanarEmbedding(test.g)
+		if ok != test.planar {
+			t.Errorf("Test %v - Found: %v Expected: %v", i, ok, test.planar)
+			continue
+		}
+		if ok && !checkRotationSystem(test.g, rotation) {
+			t.Errorf("Test %v - %v is not a planar embedding", i, rotation)
+		}
+		if !ok && !isKuratowskiSubgraph(test.g, k) {
+			t.Errorf("Test %v - %v is not a Kuratowski subgraph", i, graph.Sparse6Encode(k))
+		}
+	}
 }
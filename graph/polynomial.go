@@ -0,0 +1,760 @@
+package graph
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"math/bits"
+
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+//polyAdd returns a + b where the polynomials are given by their coefficients starting with the constant term.
+func polyAdd(a, b []*big.Int) []*big.Int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	sum := make([]*big.Int, len(a))
+	for i := range a {
+		sum[i] = new(big.Int).Set(a[i])
+		if i < len(b) {
+			sum[i].Add(sum[i], b[i])
+		}
+	}
+	return sum
+}
+
+//polyNeg returns -a.
+func polyNeg(a []*big.Int) []*big.Int {
+	neg := make([]*big.Int, len(a))
+	for i := range a {
+		neg[i] = new(big.Int).Neg(a[i])
+	}
+	return neg
+}
+
+//polyMul returns ab.
+func polyMul(a, b []*big.Int) []*big.Int {
+	if len(a) == 0 || len(b) == 0 {
+		return []*big.Int{}
+	}
+	product := make([]*big.Int, len(a)+len(b)-1)
+	for i := range product {
+		product[i] = new(big.Int)
+	}
+	tmp := new(big.Int)
+	for i := range a {
+		if a[i].Sign() == 0 {
+			continue
+		}
+		for j := range b {
+			product[i+j].Add(product[i+j], tmp.Mul(a[i], b[j]))
+		}
+	}
+	return product
+}
+
+//polyMulLinear returns a(x - d).
+func polyMulLinear(a []*big.Int, d int) []*big.Int {
+	product := make([]*big.Int, len(a)+1)
+	for i := range product {
+		product[i] = new(big.Int)
+	}
+	bigD := big.NewInt(int64(d))
+	tmp := new(big.Int)
+	for i := range a {
+		product[i+1].Add(product[i+1], a[i])
+		product[i].Sub(product[i], tmp.Mul(a[i], bigD))
+	}
+	return product
+}
+
+//ChromaticPolynomial returns the coefficients of the chromatic polynomial of g starting with the constant term.
+//The graph is split into blocks since the chromatic polynomial of a connected graph is the product of the chromatic polynomials of its blocks divided by x^(b - 1) where b is the number of blocks. Removing a simplicial vertex of degree d (a vertex whose neighbourhood is a clique) divides the chromatic polynomial by x - d so trees, complete graphs and chordal graphs are handled without branching and cycles are recognised directly. A block whose complement is disconnected is the join of smaller graphs and is handled using the number of partitions of each graph into independent sets.
+//The other blocks are found by adding the vertices one at a time and storing a polynomial for each partition of the frontier (the added vertices with a neighbour which hasn't been added) into colour classes. This is fast when there is an order of the vertices with a small frontier. If the number of partitions is estimated to be too large, the block is split using deletion-contraction, or addition-contraction if the block is dense, and the chromatic polynomials are cached. The cache is keyed by the degree sequence and the canonical isomorph is only found when two graphs have the same degree sequence.
+func ChromaticPolynomial(g Graph) []*big.Int {
+	n := g.N()
+	edges := make([]byte, (n*(n-1))/2)
+	index := 0
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if g.IsEdge(i, j) {
+				edges[index] = 1
+			}
+			index++
+		}
+	}
+	cs := &chromaticState{
+		cache:   make(map[string]*chromaticBucket),
+		op:      NewOrderedPartition(n, len(edges), nil),
+		storage: NewStorage(n, len(edges)),
+		options: new(CanonicalOptions),
+		moduli:  chromaticModuli(n),
+		product: new(big.Int).Lsh(big.NewInt(1), 64),
+	}
+	for _, p := range cs.moduli[1:] {
+		cs.product.Mul(cs.product, new(big.Int).SetUint64(p))
+	}
+	cs.half = new(big.Int).Rsh(cs.product, 1)
+	for _, p := range cs.moduli {
+		m := new(big.Int).SetUint64(p)
+		if p == 0 {
+			m.Lsh(big.NewInt(1), 64)
+		}
+		others := new(big.Int).Quo(cs.product, m)
+		cs.crt = append(cs.crt, others.Mul(others, new(big.Int).ModInverse(others, m)))
+	}
+	return cs.chromaticPolynomial(NewDense(n, edges))
+}
+
+//chromaticState holds the cache of the chromatic polynomials of 2-connected graphs and the storage for finding their canonical isomorphs.
+type chromaticState struct {
+	cache   map[string]*chromaticBucket
+	op      *CanonicalOrderedPartition
+	storage *CanonicalStorage
+	options *CanonicalOptions
+
+	//The moduli used by frontierChromaticPolynomial, their product, half of their product and the values which are 1 modulo one of the moduli and 0 modulo the others.
+	moduli  []uint64
+	product *big.Int
+	half    *big.Int
+	crt     []*big.Int
+}
+
+//chromaticBucket holds the chromatic polynomials of the graphs with the same invariant.
+//Finding the canonical isomorph is much slower than computing the invariant so the first graph in a bucket is stored as it is and the graphs are only canonically labelled once a second graph with the same invariant is found.
+type chromaticBucket struct {
+	g         *DenseGraph
+	p         []*big.Int
+	canonical map[string][]*big.Int
+}
+
+//invariantKey returns a string containing the number of vertices, the number of edges and the sorted degree sequence of g.
+func invariantKey(g *DenseGraph) string {
+	degrees := g.Degrees()
+	ints.Sort(degrees)
+	key := make([]byte, 0, 2*binary.MaxVarintLen64+len(degrees))
+	tmp := make([]byte, binary.MaxVarintLen64)
+	for _, x := range append([]int{g.N(), g.M()}, degrees...) {
+		key = append(key, tmp[:binary.PutUvarint(tmp, uint64(x))]...)
+	}
+	return string(key)
+}
+
+//lookup returns the chromatic polynomial of g if it is in the cache. If g isn't in the cache, lookup also returns the canonical key of g if it was needed.
+func (cs *chromaticState) lookup(g *DenseGraph, neighbours [][]int, invariant string) (p []*big.Int, key string, ok bool) {
+	b := cs.cache[invariant]
+	if b == nil {
+		return nil, "", false
+	}
+	key = cs.canonicalKey(g, neighbours)
+	if b.canonical == nil {
+		b.canonical = map[string][]*big.Int{cs.canonicalKey(b.g, nil): b.p}
+		b.g, b.p = nil, nil
+	}
+	p, ok = b.canonical[key]
+	return p, key, ok
+}
+
+//store adds the chromatic polynomial of g to the cache. The key should be the canonical key returned by lookup or the empty string if lookup didn't return one.
+func (cs *chromaticState) store(g *DenseGraph, neighbours [][]int, invariant, key string, p []*big.Int) {
+	b := cs.cache[invariant]
+	if b == nil {
+		cs.cache[invariant] = &chromaticBucket{g: g, p: p}
+		return
+	}
+	if key == "" {
+		key = cs.canonicalKey(g, neighbours)
+	}
+	if b.canonical == nil {
+		b.canonical = map[string][]*big.Int{cs.canonicalKey(b.g, nil): b.p}
+		b.g, b.p = nil, nil
+	}
+	b.canonical[key] = p
+}
+
+//canonicalKey returns a string which is the same for two graphs if and only if they are isomorphic. If neighbours is nil, the neighbourhoods are found from g.
+func (cs *chromaticState) canonicalKey(g *DenseGraph, neighbours [][]int) string {
+	n := g.N()
+	if neighbours == nil {
+		neighbours = make([][]int, n)
+		for v := range neighbours {
+			neighbours[v] = g.Neighbours(v)
+		}
+	}
+	cs.op.Reset(n, g.M(), nil)
+	perm, _, _ := CanonicalIsomorphAllocated(n, g.M(), neighbours, cs.op, cs.storage, cs.options)
+	key := make([]byte, 1+(n*(n-1)/2+7)/8)
+	key[0] = byte(n)
+	index := 8
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if g.IsEdge(perm[i], perm[j]) {
+				key[index/8] |= 1 << uint(index%8)
+			}
+			index++
+		}
+	}
+	return string(key)
+}
+
+//chromaticPolynomial returns the chromatic polynomial of g. This modifies g.
+func (cs *chromaticState) chromaticPolynomial(g *DenseGraph) []*big.Int {
+	poly := []*big.Int{big.NewInt(1)}
+	for {
+		v, d := simplicialVertex(g)
+		if v == -1 {
+			break
+		}
+		poly = polyMulLinear(poly, d)
+		g.RemoveVertex(v)
+	}
+	n := g.N()
+	if n == 0 {
+		return poly
+	}
+	neighbours := make([][]int, n)
+	for v := range neighbours {
+		neighbours[v] = g.Neighbours(v)
+	}
+
+	//The chromatic polynomial is x^c multiplied by P(B)/x for each block B where c is the number of components.
+	blocks := blocks(neighbours)
+	if len(blocks) > 1 {
+		c := n
+		for _, b := range blocks {
+			c -= len(b) - 1
+			h := g.InducedSubgraph(b).(*DenseGraph)
+			poly = polyMul(poly, cs.chromaticPolynomial(h)[1:])
+		}
+		for i := 0; i < c; i++ {
+			poly = append([]*big.Int{new(big.Int)}, poly...)
+		}
+		return poly
+	}
+
+	//g is 2-connected and isn't complete.
+	if g.M() == n {
+		return polyMul(poly, cyclePolynomial(n))
+	}
+
+	//If the complement of g is disconnected, g is the join of the graphs induced by the components of the complement. An independent set of g is contained in one of the graphs so the number of partitions of g into k independent sets can be found from the number of partitions of each graph.
+	if components := coComponents(g); len(components) > 1 {
+		partitions := []*big.Int{big.NewInt(1)}
+		for _, c := range components {
+			h := g.InducedSubgraph(c).(*DenseGraph)
+			partitions = polyMul(partitions, cs.toFallingFactorial(cs.chromaticPolynomial(h)))
+		}
+		return polyMul(poly, cs.fromFallingFactorial(partitions))
+	}
+	invariant := invariantKey(g)
+	p, key, ok := cs.lookup(g, neighbours, invariant)
+	if ok {
+		return polyMul(poly, p)
+	}
+
+	//Use the frontier dynamic programming if the number of states is estimated to be small.
+	order, estimate := frontierOrder(g, neighbours)
+	if estimate <= directFrontierStates {
+		if p, ok = cs.frontierChromaticPolynomial(g, neighbours, order, maxChromaticStates); ok {
+			cs.store(g, neighbours, invariant, key, p)
+			return polyMul(poly, p)
+		}
+	}
+
+	//Branch on a non-edge of a dense graph to make it closer to a join and branch on an edge of a sparse graph to make it closer to having a cut vertex. The addition uses the vertex of largest degree which isn't adjacent to every vertex and the deletion uses the vertex of smallest degree. Both are joined to the vertex of largest degree among the candidates.
+	degrees := g.DegreeSequence
+	add := 3*g.M() > n*(n-1)/2
+	u, v := -1, -1
+	if add {
+		for i := 0; i < n; i++ {
+			if degrees[i] < n-1 && (u == -1 || degrees[i] > degrees[u]) {
+				u = i
+			}
+		}
+		for i := 0; i < n; i++ {
+			if i != u && !g.IsEdge(u, i) && (v == -1 || degrees[i] > degrees[v]) {
+				v = i
+			}
+		}
+	} else {
+		u = 0
+		for i := 1; i < n; i++ {
+			if degrees[i] < degrees[u] {
+				u = i
+			}
+		}
+		for _, i := range neighbours[u] {
+			if v == -1 || degrees[i] > degrees[v] {
+				v = i
+			}
+		}
+	}
+	if u > v {
+		u, v = v, u
+	}
+	h1 := g.Copy().(*DenseGraph)
+	if add {
+		h1.AddEdge(u, v)
+	} else {
+		h1.RemoveEdge(u, v)
+	}
+	h2 := g.Copy().(*DenseGraph)
+	Contract(h2, u, v)
+
+	//Branching doesn't help if the estimated number of states for the two new graphs is not much smaller than for g.
+	if estimate <= maxFrontierEstimate {
+		sum := 0.0
+		for _, h := range []*DenseGraph{h1, h2} {
+			hNeighbours := make([][]int, h.N())
+			for x := range hNeighbours {
+				hNeighbours[x] = h.Neighbours(x)
+			}
+			_, hEstimate := frontierOrder(h, hNeighbours)
+			sum += hEstimate
+		}
+		if 2*sum >= 3*estimate {
+			if p, ok = cs.frontierChromaticPolynomial(g, neighbours, order, maxChromaticStates); ok {
+				cs.store(g, neighbours, invariant, key, p)
+				return polyMul(poly, p)
+			}
+		}
+	}
+
+	p = cs.chromaticPolynomial(h1)
+	if add {
+		p = polyAdd(p, cs.chromaticPolynomial(h2))
+	} else {
+		p = polyAdd(p, polyNeg(cs.chromaticPolynomial(h2)))
+	}
+	cs.store(g, neighbours, invariant, key, p)
+	return polyMul(poly, p)
+}
+
+//directFrontierStates is the estimated number of states below which frontierChromaticPolynomial is always tried, maxFrontierEstimate is the estimate above which chromaticPolynomial always branches and maxChromaticStates is the number of states at which frontierChromaticPolynomial gives up.
+const (
+	directFrontierStates = 1 << 14
+	maxFrontierEstimate  = 1 << 19
+	maxChromaticStates   = 1 << 20
+)
+
+//frontierOrder returns an order of the vertices of g which keeps the frontier (the added vertices with a neighbour which hasn't been added) small and an estimate of the largest number of partitions of the frontier into independent sets.
+//The next vertex is chosen greedily to give the smallest frontier and every vertex is tried as the first vertex. The estimate for a frontier with f vertices and density q is the expected number of partitions of the random graph G(f, q) into independent sets.
+func frontierOrder(g *DenseGraph, neighbours [][]int) ([]int, float64) {
+	var best []int
+	bestEstimate := 0.0
+	for v := 0; v < g.N(); v++ {
+		order, estimate := frontierOrderFrom(g, neighbours, v)
+		if best == nil || estimate < bestEstimate {
+			best, bestEstimate = order, estimate
+		}
+	}
+	return best, bestEstimate
+}
+
+//frontierOrderFrom returns the order found by frontierOrder which starts at the vertex start and its estimate.
+func frontierOrderFrom(g *DenseGraph, neighbours [][]int, start int) ([]int, float64) {
+	n := g.N()
+	order := make([]int, 0, n)
+	added := make([]bool, n)
+	addedNeighbours := make([]int, n)
+	frontier := make([]int, 0, n)
+	estimate := 0.0
+	partitions := make([]float64, n+1)
+	for len(order) < n {
+		//Choose the vertex which gives the smallest frontier and then has the most added neighbours.
+		best := start
+		if len(order) > 0 {
+			best = -1
+			bestSize := 0
+			for v := 0; v < n; v++ {
+				if added[v] {
+					continue
+				}
+				size := 0
+				if addedNeighbours[v] < g.DegreeSequence[v] {
+					size++
+				}
+				for _, u := range neighbours[v] {
+					if added[u] && addedNeighbours[u]+1 == g.DegreeSequence[u] {
+						size--
+					}
+				}
+				if best == -1 || size < bestSize || (size == bestSize && addedNeighbours[v] > addedNeighbours[best]) {
+					best, bestSize = v, size
+				}
+			}
+		}
+		added[best] = true
+		order = append(order, best)
+		for _, u := range neighbours[best] {
+			addedNeighbours[u]++
+		}
+		frontier = append(frontier, best)
+		f := 0
+		for _, u := range frontier {
+			if addedNeighbours[u] < g.DegreeSequence[u] {
+				frontier[f] = u
+				f++
+			}
+		}
+		frontier = frontier[:f]
+		if f < 2 {
+			continue
+		}
+
+		//The expected number of partitions satisfies T(k) = sum_s C(k - 1, s - 1) (1 - q)^C(s, 2) T(k - s) where s is the size of the part containing the first vertex.
+		edges := 0
+		for i, u := range frontier {
+			for _, w := range frontier[:i] {
+				if g.IsEdge(u, w) {
+					edges++
+				}
+			}
+		}
+		q := float64(edges) / float64(f*(f-1)/2)
+		partitions[0] = 1
+		for k := 1; k <= f; k++ {
+			partitions[k] = 0
+			binomial := 1.0
+			for s := 1; s <= k; s++ {
+				partitions[k] += binomial * math.Pow(1-q, float64(s*(s-1)/2)) * partitions[k-s]
+				binomial *= float64(k-s) / float64(s)
+			}
+		}
+		if partitions[f] > estimate {
+			estimate = partitions[f]
+		}
+	}
+	return order, estimate
+}
+
+//frontierChromaticPolynomial returns the chromatic polynomial of g and true or nil and false if more than maxStates states are needed. The vertices are added one at a time in the given order and, for each partition of the frontier into colour classes, the polynomial counting the colourings of the added vertices which induce this partition is stored.
+//A new vertex either joins a colour class without any of its neighbours or gets one of the x - b colours not used on the b classes of the frontier. The vertices which aren't on the frontier don't restrict the colours of the remaining vertices so they can be forgotten.
+//The coefficients are stored modulo each of cs.moduli and found at the end using the Chinese remainder theorem.
+func (cs *chromaticState) frontierChromaticPolynomial(g *DenseGraph, neighbours [][]int, order []int, maxStates int) ([]*big.Int, bool) {
+	n := g.N()
+	remaining := make([]int, n)
+	copy(remaining, g.DegreeSequence)
+	isNeighbour := make([]bool, n)
+
+	//The state is the colour class of each vertex of the frontier numbered in order of their first appearance. The values are stored with the coefficients modulo cs.moduli[j] in values[index*stride+j*(n+1):].
+	stride := len(cs.moduli) * (n + 1)
+	frontier := []int{}
+	states := map[string]int{"": 0}
+	values := make([]uint64, stride)
+	for j := range cs.moduli {
+		values[j*(n+1)] = 1
+	}
+	for added, v := range order {
+		for _, u := range neighbours[v] {
+			isNeighbour[u] = true
+			remaining[u]--
+		}
+		newFrontier := make([]int, 0, len(frontier)+1)
+		keep := make([]int, 0, len(frontier))
+		for j, u := range frontier {
+			if remaining[u] > 0 {
+				newFrontier = append(newFrontier, u)
+				keep = append(keep, j)
+			}
+		}
+		keepV := remaining[v] > 0
+		if keepV {
+			newFrontier = append(newFrontier, v)
+		}
+
+		newStates := make(map[string]int)
+		newValues := make([]uint64, 0, len(values))
+		blocked := make([]bool, len(frontier)+1)
+		relabel := make([]int, len(frontier)+1)
+		buf := make([]byte, len(newFrontier))
+		for state, index := range states {
+			value := values[index*stride : (index+1)*stride]
+			classes := 0
+			for j := range blocked {
+				blocked[j] = false
+			}
+			for j, u := range frontier {
+				if int(state[j]) >= classes {
+					classes = int(state[j]) + 1
+				}
+				if isNeighbour[u] {
+					blocked[state[j]] = true
+				}
+			}
+			//Renumber the classes of the vertices which stay on the frontier.
+			for j := range relabel {
+				relabel[j] = -1
+			}
+			next := 0
+			for x, j := range keep {
+				if relabel[state[j]] == -1 {
+					relabel[state[j]] = next
+					next++
+				}
+				buf[x] = byte(relabel[state[j]])
+			}
+			for c := 0; c <= classes; c++ {
+				if c < classes && blocked[c] {
+					continue
+				}
+				//Find the state after adding v to the class c.
+				if keepV {
+					if c < classes && relabel[c] != -1 {
+						buf[len(keep)] = byte(relabel[c])
+					} else {
+						buf[len(keep)] = byte(next)
+					}
+				}
+				newIndex, ok := newStates[string(buf)]
+				if !ok {
+					if len(newStates) == maxStates {
+						return nil, false
+					}
+					newIndex = len(newStates)
+					newStates[string(buf)] = newIndex
+					newValues = append(newValues, make([]uint64, stride)...)
+				}
+				newValue := newValues[newIndex*stride : (newIndex+1)*stride]
+				for j, p := range cs.moduli {
+					a := newValue[j*(n+1) : j*(n+1)+added+2]
+					b := value[j*(n+1) : j*(n+1)+added+1]
+					if c < classes {
+						addMod(a, b, p)
+					} else {
+						//Multiply by x - classes.
+						addMod(a[1:], b, p)
+						subMulMod(a, b, uint64(classes), p)
+					}
+				}
+			}
+		}
+		for _, u := range neighbours[v] {
+			isNeighbour[u] = false
+		}
+		frontier = newFrontier
+		states = newStates
+		values = newValues
+	}
+
+	p := make([]*big.Int, n+1)
+	tmp := new(big.Int)
+	for i := range p {
+		p[i] = new(big.Int)
+		for j := range cs.moduli {
+			p[i].Add(p[i], tmp.Mul(tmp.SetUint64(values[j*(n+1)+i]), cs.crt[j]))
+		}
+		p[i].Mod(p[i], cs.product)
+		if p[i].Cmp(cs.half) > 0 {
+			p[i].Sub(p[i], cs.product)
+		}
+	}
+	return p, true
+}
+
+//addMod sets a[i] = a[i] + b[i] mod p for each i < len(b). The modulus 0 means 2^64.
+func addMod(a, b []uint64, p uint64) {
+	if p == 0 {
+		for i := range b {
+			a[i] += b[i]
+		}
+		return
+	}
+	for i := range b {
+		a[i] += b[i]
+		if a[i] >= p {
+			a[i] -= p
+		}
+	}
+}
+
+//subMulMod sets a[i] = a[i] - c * b[i] mod p for each i < len(b). The modulus 0 means 2^64 and otherwise c must be less than p.
+func subMulMod(a, b []uint64, c, p uint64) {
+	if p == 0 {
+		for i := range b {
+			a[i] -= c * b[i]
+		}
+		return
+	}
+	for i := range b {
+		hi, lo := bits.Mul64(c, b[i])
+		_, r := bits.Div64(hi, lo, p)
+		if a[i] >= r {
+			a[i] -= r
+		} else {
+			a[i] += p - r
+		}
+	}
+}
+
+//chromaticModuli returns the moduli used by frontierChromaticPolynomial for graphs with at most n vertices. The coefficients of the chromatic polynomial of a graph on n vertices are at most n! in absolute value so the moduli are 2^64 (stored as 0) and enough primes just below 2^62 for their product to be more than 2 n!.
+func chromaticModuli(n int) []uint64 {
+	bound := new(big.Int).MulRange(1, int64(n))
+	bound.Lsh(bound, 1)
+	moduli := []uint64{0}
+	product := new(big.Int).Lsh(big.NewInt(1), 64)
+	p := new(big.Int).SetUint64(1<<62 - 1)
+	for product.Cmp(bound) <= 0 {
+		for !p.ProbablyPrime(0) {
+			p.Sub(p, big.NewInt(2))
+		}
+		moduli = append(moduli, p.Uint64())
+		product.Mul(product, p)
+		p.Sub(p, big.NewInt(2))
+	}
+	return moduli
+}
+
+//toFallingFactorial returns the coefficients of p in the basis x, x(x - 1), x(x - 1)(x - 2)... starting with the constant term. The coefficients of a chromatic polynomial in this basis are the number of partitions of the vertices into a given number of independent sets.
+func (cs *chromaticState) toFallingFactorial(p []*big.Int) []*big.Int {
+	//Write x^j = sum_k S(j, k) x(x - 1)...(x - k + 1) where S(j, k) are the Stirling numbers of the second kind.
+	stirling := []*big.Int{big.NewInt(1)}
+	a := make([]*big.Int, len(p))
+	for k := range a {
+		a[k] = new(big.Int)
+	}
+	tmp := new(big.Int)
+	for j := range p {
+		if j > 0 {
+			next := make([]*big.Int, j+1)
+			next[0] = new(big.Int)
+			for k := 1; k <= j; k++ {
+				next[k] = new(big.Int)
+				if k < j {
+					next[k].Mul(stirling[k], big.NewInt(int64(k)))
+				}
+				next[k].Add(next[k], stirling[k-1])
+			}
+			stirling = next
+		}
+		for k := range stirling {
+			a[k].Add(a[k], tmp.Mul(p[j], stirling[k]))
+		}
+	}
+	return a
+}
+
+//fromFallingFactorial returns the coefficients of the polynomial with the coefficients a in the basis x, x(x - 1), x(x - 1)(x - 2)... starting with the constant term.
+func (cs *chromaticState) fromFallingFactorial(a []*big.Int) []*big.Int {
+	p := []*big.Int{}
+	falling := []*big.Int{big.NewInt(1)}
+	for k := range a {
+		term := make([]*big.Int, len(falling))
+		for i := range falling {
+			term[i] = new(big.Int).Mul(falling[i], a[k])
+		}
+		p = polyAdd(p, term)
+		falling = polyMulLinear(falling, k)
+	}
+	return p
+}
+
+//coComponents returns the vertices in each component of the complement of g.
+func coComponents(g *DenseGraph) [][]int {
+	n := g.N()
+	seen := make([]bool, n)
+	components := make([][]int, 0, 1)
+	for v := 0; v < n; v++ {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		component := []int{v}
+		for i := 0; i < len(component); i++ {
+			u := component[i]
+			for w := 0; w < n; w++ {
+				if !seen[w] && w != u && !g.IsEdge(u, w) {
+					seen[w] = true
+					component = append(component, w)
+				}
+			}
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+//blocks returns the vertices in each block of the graph with the given neighbourhoods. An isolated vertex is not in any block.
+func blocks(neighbours [][]int) [][]int {
+	n := len(neighbours)
+	depths := make([]int, n)
+	lowpoints := make([]int, n)
+	stack := make([]int, 0, n)
+	blocks := make([][]int, 0, 1)
+	var dfs func(v, depth int)
+	dfs = func(v, depth int) {
+		depths[v] = depth
+		lowpoints[v] = depth
+		stack = append(stack, v)
+		for _, u := range neighbours[v] {
+			if depths[u] == 0 {
+				dfs(u, depth+1)
+				if lowpoints[u] < lowpoints[v] {
+					lowpoints[v] = lowpoints[u]
+				}
+				if lowpoints[u] >= depth {
+					//v separates the vertices above u on the stack from the rest of the graph.
+					i := len(stack) - 1
+					for stack[i] != u {
+						i--
+					}
+					blocks = append(blocks, append([]int{v}, stack[i:]...))
+					stack = stack[:i]
+				}
+			} else if depths[u] < lowpoints[v] {
+				lowpoints[v] = depths[u]
+			}
+		}
+	}
+	for v := 0; v < n; v++ {
+		if depths[v] == 0 {
+			dfs(v, 1)
+			stack = stack[:0]
+		}
+	}
+	return blocks
+}
+
+//simplicialVertex returns a vertex whose neighbourhood is a clique and its degree or -1, -1 if there is no such vertex.
+func simplicialVertex(g *DenseGraph) (int, int) {
+	for v := 0; v < g.N(); v++ {
+		neighbours := g.Neighbours(v)
+		simplicial := true
+	pairLoop:
+		for i, u := range neighbours {
+			for _, w := range neighbours[:i] {
+				if !g.IsEdge(u, w) {
+					simplicial = false
+					break pairLoop
+				}
+			}
+		}
+		if simplicial {
+			return v, len(neighbours)
+		}
+	}
+	return -1, -1
+}
+
+//cyclePolynomial returns the chromatic polynomial (x - 1)^n + (-1)^n (x - 1) of the cycle on n vertices.
+func cyclePolynomial(n int) []*big.Int {
+	poly := make([]*big.Int, n+1)
+	binomial := big.NewInt(1)
+	for k := 0; k <= n; k++ {
+		poly[k] = new(big.Int).Set(binomial)
+		if (n-k)%2 == 1 {
+			poly[k].Neg(poly[k])
+		}
+		binomial.Mul(binomial, big.NewInt(int64(n-k)))
+		binomial.Quo(binomial, big.NewInt(int64(k+1)))
+	}
+	if n%2 == 0 {
+		poly[1].Add(poly[1], big.NewInt(1))
+		poly[0].Sub(poly[0], big.NewInt(1))
+	} else {
+		poly[1].Sub(poly[1], big.NewInt(1))
+		poly[0].Add(poly[0], big.NewInt(1))
+	}
+	return poly
+}
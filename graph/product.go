@@ -0,0 +1,175 @@
+package graph
+
+import "github.com/Tom-Johnston/mamba/sortints"
+
+//productNeighbourhoods returns the neighbourhoods of a graph on g.N()h.N() vertices where the vertex (u, v) has index u*h.N() + v and adjacent returns the neighbours of the vertex (u, v).
+func productNeighbourhoods(g, h Graph, adjacent func(u, v int, gNeighbours, hNeighbours []int) []int) []sortints.SortedInts {
+	n1, n2 := g.N(), h.N()
+	gNeighbourhoods := make([][]int, n1)
+	for u := range gNeighbourhoods {
+		gNeighbourhoods[u] = g.Neighbours(u)
+	}
+	hNeighbourhoods := make([][]int, n2)
+	for v := range hNeighbourhoods {
+		hNeighbourhoods[v] = h.Neighbours(v)
+	}
+	neighbourhoods := make([]sortints.SortedInts, n1*n2)
+	for u := 0; u < n1; u++ {
+		for v := 0; v < n2; v++ {
+			neighbourhoods[u*n2+v] = adjacent(u, v, gNeighbourhoods[u], hNeighbourhoods[v])
+		}
+	}
+	return neighbourhoods
+}
+
+//CartesianProduct returns the Cartesian product of g and h. The vertex (u, v) has index u*h.N() + v and (u, v) is adjacent to (u', v') if u = u' and v is adjacent to v' in h or v = v' and u is adjacent to u' in g.
+func CartesianProduct(g, h Graph) EditableGraph {
+	n2 := h.N()
+	neighbourhoods := productNeighbourhoods(g, h, func(u, v int, gNeighbours, hNeighbours []int) []int {
+		neighbours := make([]int, 0, len(gNeighbours)+len(hNeighbours))
+		for _, x := range gNeighbours {
+			neighbours = append(neighbours, x*n2+v)
+		}
+		for _, y := range hNeighbours {
+			neighbours = append(neighbours, u*n2+y)
+		}
+		return neighbours
+	})
+	return NewSparse(g.N()*n2, neighbourhoods)
+}
+
+//TensorProduct returns the tensor (or categorical) product of g and h. The vertex (u, v) has index u*h.N() + v and (u, v) is adjacent to (u', v') if u is adjacent to u' in g and v is adjacent to v' in h.
+func TensorProduct(g, h Graph) EditableGraph {
+	n2 := h.N()
+	neighbourhoods := productNeighbourhoods(g, h, func(u, v int, gNeighbours, hNeighbours []int) []int {
+		neighbours := make([]int, 0, len(gNeighbours)*len(hNeighbours))
+		for _, x := range gNeighbours {
+			for _, y := range hNeighbours {
+				neighbours = append(neighbours, x*n2+y)
+			}
+		}
+		return neighbours
+	})
+	return NewSparse(g.N()*n2, neighbourhoods)
+}
+
+//StrongProduct returns the strong product of g and h. The vertex (u, v) has index u*h.N() + v and the edges are the edges of the Cartesian product and the tensor product.
+func StrongProduct(g, h Graph) EditableGraph {
+	n2 := h.N()
+	neighbourhoods := productNeighbourhoods(g, h, func(u, v int, gNeighbours, hNeighbours []int) []int {
+		neighbours := make([]int, 0, (len(gNeighbours)+1)*(len(hNeighbours)+1)-1)
+		for _, y := range hNeighbours {
+			neighbours = append(neighbours, u*n2+y)
+		}
+		for _, x := range gNeighbours {
+			neighbours = append(neighbours, x*n2+v)
+			for _, y := range hNeighbours {
+				neighbours = append(neighbours, x*n2+y)
+			}
+		}
+		return neighbours
+	})
+	return NewSparse(g.N()*n2, neighbourhoods)
+}
+
+//LexicographicProduct returns the lexicographic product g[h] of g and h. The vertex (u, v) has index u*h.N() + v and (u, v) is adjacent to (u', v') if u is adjacent to u' in g or u = u' and v is adjacent to v' in h.
+//This can be thought of as replacing each vertex of g with a copy of h and each edge of g with a complete bipartite graph.
+func LexicographicProduct(g, h Graph) EditableGraph {
+	n2 := h.N()
+	neighbourhoods := productNeighbourhoods(g, h, func(u, v int, gNeighbours, hNeighbours []int) []int {
+		neighbours := make([]int, 0, len(gNeighbours)*n2+len(hNeighbours))
+		for _, x := range gNeighbours {
+			for y := 0; y < n2; y++ {
+				neighbours = append(neighbours, x*n2+y)
+			}
+		}
+		for _, y := range hNeighbours {
+			neighbours = append(neighbours, u*n2+y)
+		}
+		return neighbours
+	})
+	return NewSparse(g.N()*n2, neighbourhoods)
+}
+
+//DisjointUnion returns the disjoint union of g and h. The vertex u of g has index u and the vertex v of h has index g.N() + v.
+func DisjointUnion(g, h Graph) EditableGraph {
+	return join(g, h, false)
+}
+
+//Join returns the join of g and h which is the disjoint union of g and h with every edge between g and h added. The vertex u of g has index u and the vertex v of h has index g.N() + v.
+func Join(g, h Graph) EditableGraph {
+	return join(g, h, true)
+}
+
+//join returns the disjoint union of g and h with every edge between them if complete is true.
+func join(g, h Graph, complete bool) EditableGraph {
+	n1, n2 := g.N(), h.N()
+	neighbourhoods := make([]sortints.SortedInts, n1+n2)
+	for u := 0; u < n1; u++ {
+		neighbourhoods[u] = append(sortints.SortedInts{}, g.Neighbours(u)...)
+		if complete {
+			for v := 0; v < n2; v++ {
+				neighbourhoods[u] = append(neighbourhoods[u], n1+v)
+			}
+		}
+	}
+	for v := 0; v < n2; v++ {
+		neighbours := h.Neighbours(v)
+		neighbourhoods[n1+v] = make(sortints.SortedInts, 0, len(neighbours))
+		if complete {
+			for u := 0; u < n1; u++ {
+				neighbourhoods[n1+v] = append(neighbourhoods[n1+v], u)
+			}
+		}
+		for _, w := range neighbours {
+			neighbourhoods[n1+v] = append(neighbourhoods[n1+v], n1+w)
+		}
+	}
+	return NewSparse(n1+n2, neighbourhoods)
+}
+
+//Corona returns the corona of g and h which is formed from g and g.N() copies of h by joining the vertex u of g to every vertex in the uth copy of h. The vertex u of g has index u and the vertex v in the uth copy of h has index g.N() + u*h.N() + v.
+func Corona(g, h Graph) EditableGraph {
+	n1, n2 := g.N(), h.N()
+	hNeighbourhoods := make([][]int, n2)
+	for v := range hNeighbourhoods {
+		hNeighbourhoods[v] = h.Neighbours(v)
+	}
+	neighbourhoods := make([]sortints.SortedInts, n1+n1*n2)
+	for u := 0; u < n1; u++ {
+		neighbourhoods[u] = append(sortints.SortedInts{}, g.Neighbours(u)...)
+		offset := n1 + u*n2
+		for v := 0; v < n2; v++ {
+			neighbourhoods[u] = append(neighbourhoods[u], offset+v)
+			neighbours := make(sortints.SortedInts, 0, len(hNeighbourhoods[v])+1)
+			neighbours = append(neighbours, u)
+			for _, w := range hNeighbourhoods[v] {
+				neighbours = append(neighbours, offset+w)
+			}
+			neighbourhoods[offset+v] = neighbours
+		}
+	}
+	return NewSparse(n1+n1*n2, neighbourhoods)
+}
+
+//RootedProduct returns the rooted product of g and h with the given root of h. This is formed from g.N() copies of h by identifying the root of the uth copy with the vertex u of g. The vertex v in the uth copy of h has index u*h.N() + v so the vertex u of g has index u*h.N() + root.
+//This panics if h has no vertices or the root is not a vertex of h.
+func RootedProduct(g, h Graph, root int) EditableGraph {
+	n2 := h.N()
+	if root < 0 || root >= n2 {
+		panic("The root is not a vertex of h.")
+	}
+	neighbourhoods := productNeighbourhoods(g, h, func(u, v int, gNeighbours, hNeighbours []int) []int {
+		neighbours := make([]int, 0, len(hNeighbours)+len(gNeighbours))
+		for _, y := range hNeighbours {
+			neighbours = append(neighbours, u*n2+y)
+		}
+		if v == root {
+			for _, x := range gNeighbours {
+				neighbours = append(neighbours, x*n2+root)
+			}
+		}
+		return neighbours
+	})
+	return NewSparse(g.N()*n2, neighbourhoods)
+}
@@ -0,0 +1,97 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+func TestProducts(t *testing.T) {
+	//The Cartesian product of K_n and K_m is the rook graph and the Cartesian product of cubes is a cube.
+	if !isomorphic(graph.CartesianProduct(graph.CompleteGraph(3), graph.CompleteGraph(4)), graph.RookGraph(3, 4)) {
+		t.Errorf("K_3 x K_4 is not the rook graph")
+	}
+	if !isomorphic(graph.CartesianProduct(graph.HypercubeGraph(2), graph.HypercubeGraph(3)), graph.HypercubeGraph(5)) {
+		t.Errorf("Q_2 x Q_3 is not Q_5")
+	}
+	//The vertex (u, v) has index u*h.N() + v.
+	g := graph.CartesianProduct(graph.Path(3), graph.Path(4))
+	if !g.IsEdge(1*4+2, 2*4+2) || !g.IsEdge(1*4+2, 1*4+3) || g.IsEdge(1*4+2, 2*4+3) || g.M() != 17 {
+		t.Errorf("Wrong edges in the 3 x 4 grid")
+	}
+
+	//The tensor product of K_2 and K_3 is C_6 and the tensor product of two bipartite graphs is disconnected.
+	if !isomorphic(graph.TensorProduct(graph.CompleteGraph(2), graph.CompleteGraph(3)), graph.Cycle(6)) {
+		t.Errorf("K_2 x K_3 is not C_6")
+	}
+	if g := graph.TensorProduct(graph.Cycle(4), graph.Path(5)); g.M() != 2*4*4 || len(graph.ConnectedComponents(g)) != 2 {
+		t.Errorf("Wrong tensor product of C_4 and P_5")
+	}
+
+	//The strong product of K_n and K_m is K_nm and the strong product of two paths is the king graph.
+	if graph.Graph6Encode(graph.StrongProduct(graph.CompleteGraph(3), graph.CompleteGraph(4))) != graph.Graph6Encode(graph.CompleteGraph(12)) {
+		t.Errorf("K_3 x K_4 is not K_12")
+	}
+	if g := graph.StrongProduct(graph.Path(8), graph.Path(8)); g.M() != 2*8*7+2*7*7 {
+		t.Errorf("Wrong number of edges in the king graph. Found: %v Expected: %v", g.M(), 2*8*7+2*7*7)
+	}
+
+	//The lexicographic product K_n[E_m] is the complete multipartite graph and the product isn't commutative.
+	if graph.Graph6Encode(graph.LexicographicProduct(graph.CompleteGraph(3), graph.NewDense(2, nil))) != graph.Graph6Encode(graph.CompletePartiteGraph(2, 2, 2)) {
+		t.Errorf("K_3[E_2] is not K_{2, 2, 2}")
+	}
+	if g := graph.LexicographicProduct(graph.Cycle(5), graph.Path(3)); g.M() != 5*2+5*9 {
+		t.Errorf("Wrong number of edges in C_5[P_3]. Found: %v Expected: %v", g.M(), 5*2+5*9)
+	}
+	if g := graph.LexicographicProduct(graph.Path(3), graph.Cycle(5)); g.M() != 3*5+2*25 {
+		t.Errorf("Wrong number of edges in P_3[C_5]. Found: %v Expected: %v", g.M(), 3*5+2*25)
+	}
+
+	//Products with the graph with no vertices have no vertices.
+	for _, product := range []func(g, h graph.Graph) graph.EditableGraph{graph.CartesianProduct, graph.TensorProduct, graph.StrongProduct, graph.LexicographicProduct} {
+		if g := product(graph.Cycle(5), graph.NewDense(0, nil)); g.N() != 0 {
+			t.Errorf("Expected the graph with no vertices")
+		}
+	}
+}
+
+func TestBinaryOperations(t *testing.T) {
+	if graph.Graph6Encode(graph.Join(graph.NewDense(3, nil), graph.NewDense(4, nil))) != graph.Graph6Encode(graph.CompletePartiteGraph(3, 4)) {
+		t.Errorf("The join of E_3 and E_4 is not K_{3, 4}")
+	}
+	if graph.Graph6Encode(graph.Join(graph.CompleteGraph(3), graph.CompleteGraph(4))) != graph.Graph6Encode(graph.CompleteGraph(7)) {
+		t.Errorf("The join of K_3 and K_4 is not K_7")
+	}
+	g := graph.DisjointUnion(graph.Cycle(5), graph.Path(4))
+	if g.N() != 9 || g.M() != 8 || len(graph.ConnectedComponents(g)) != 2 || !g.IsEdge(5, 6) || !g.IsEdge(0, 4) {
+		t.Errorf("Wrong disjoint union of C_5 and P_4")
+	}
+
+	//The corona of K_1 and E_n is a star and the corona of C_4 and K_2 has a triangle at each vertex.
+	if graph.Graph6Encode(graph.Corona(graph.CompleteGraph(1), graph.NewDense(5, nil))) != graph.Graph6Encode(graph.Star(6)) {
+		t.Errorf("The corona of K_1 and E_5 is not the star")
+	}
+	g = graph.Corona(graph.Cycle(4), graph.CompleteGraph(2))
+	if g.N() != 12 || g.M() != 4+4*1+4*2 || graph.NumberOfCycles(g)[3] != 4 || !g.IsEdge(1, 4+2*1+1) {
+		t.Errorf("Wrong corona of C_4 and K_2")
+	}
+
+	//The rooted product of a path and an edge is a comb and the rooted product with K_1 is the original graph.
+	g = graph.RootedProduct(graph.Path(5), graph.Path(2), 0)
+	if g.N() != 10 || g.M() != 9 || len(graph.ConnectedComponents(g)) != 1 || graph.MaxDegree(g) != 3 || !g.IsEdge(2, 4) || !g.IsEdge(4, 5) {
+		t.Errorf("Wrong rooted product of P_5 and K_2")
+	}
+	if graph.Graph6Encode(graph.RootedProduct(graph.Cycle(6), graph.CompleteGraph(1), 0)) != graph.Graph6Encode(graph.Cycle(6)) {
+		t.Errorf("The rooted product of C_6 and K_1 is not C_6")
+	}
+	g = graph.RootedProduct(graph.Cycle(3), graph.Star(4), 1)
+	if g.N() != 12 || g.M() != 3+3*3 || graph.MaxDegree(g) != 3 || !g.IsEdge(1, 5) {
+		t.Errorf("Wrong rooted product of K_3 and a star rooted at a leaf")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a root outside h")
+		}
+	}()
+	graph.RootedProduct(graph.Cycle(3), graph.Path(2), 2)
+}
@@ -0,0 +1,178 @@
+package search
+
+import (
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//Constraints restricts the graphs found by a GraphIterator. The options match the options of the same name in nauty's geng so the counts can be compared directly. The zero value doesn't restrict the graphs.
+//The constraints which are hereditary (i.e. they hold for every induced subgraph of a graph satisfying them) are used to prune the search as soon as they fail. The other constraints are used to prune the search when it is clear they can't be satisfied and the graphs are checked once they have n vertices.
+type Constraints struct {
+	Connected    bool //Only find connected graphs. This is -c in geng.
+	Biconnected  bool //Only find 2-connected graphs i.e. connected graphs on at least 3 vertices without a cut vertex. This is -C in geng.
+	TriangleFree bool //Only find graphs without a triangle. This is -t in geng.
+	C4Free       bool //Only find graphs without a cycle of length 4 as a (not necessarily induced) subgraph. This is -f in geng.
+	Bipartite    bool //Only find bipartite graphs. This is -b in geng.
+
+	MinDegree int //Only find graphs with minimum degree at least MinDegree. This is -d in geng.
+	MaxDegree int //Only find graphs with maximum degree at most MaxDegree. This is -D in geng. If MaxDegree is 0, there is no restriction.
+	MinEdges  int //Only find graphs with at least MinEdges edges. This is mine in geng.
+	MaxEdges  int //Only find graphs with at most MaxEdges edges. This is maxe in geng. If MaxEdges is 0, there is no restriction.
+}
+
+//WithConstraints with a = 0 and m = 1 returns a *GraphIterator which iterates over all non-isomorphic graphs on n vertices satisfying the constraints such that neither the graph itself nor any of its predecessors were pruned by preprune or prune.
+//The functions preprune and prune are used exactly as in WithPruning after the constraints have been checked and either can be nil. See WithPruning for the meaning of a and m.
+func WithConstraints(n int, a int, m int, constraints Constraints, preprune, prune func(g *graph.DenseGraph) bool) *GraphIterator {
+	if preprune == nil {
+		preprune = func(g *graph.DenseGraph) bool { return false }
+	}
+	if prune == nil {
+		prune = func(g *graph.DenseGraph) bool { return false }
+	}
+	c := constraints
+	//Don't wrap the functions if there are no constraints.
+	if c == (Constraints{}) {
+		return WithPruning(n, a, m, preprune, prune)
+	}
+	constrainedPreprune := func(g *graph.DenseGraph) bool {
+		return c.preprune(g, n) || preprune(g)
+	}
+	constrainedPrune := func(g *graph.DenseGraph) bool {
+		return c.prune(g, n) || prune(g)
+	}
+	iter := WithPruning(n, a, m, constrainedPreprune, constrainedPrune)
+	iter.constraints = c
+	return iter
+}
+
+//preprune returns true if no graph on n vertices satisfying the constraints can be found from g. The last vertex of g is the vertex which has just been added and all the earlier graphs in the search have already been checked.
+func (c Constraints) preprune(g *graph.DenseGraph, n int) bool {
+	k := g.N()
+	if k == 0 {
+		return false
+	}
+	v := k - 1
+	neighbours := g.Neighbours(v)
+	degrees := g.DegreeSequence
+
+	if c.MaxEdges > 0 && g.M() > c.MaxEdges {
+		return true
+	}
+
+	if c.MaxDegree > 0 {
+		if degrees[v] > c.MaxDegree {
+			return true
+		}
+		for _, u := range neighbours {
+			if degrees[u] > c.MaxDegree {
+				return true
+			}
+		}
+	}
+
+	//Each of the remaining vertices adds at most 1 to the degree of each vertex.
+	if c.MinDegree > 0 {
+		for _, d := range degrees {
+			if d+n-k < c.MinDegree {
+				return true
+			}
+		}
+	}
+
+	//The vertex added when there are j vertices has degree at most j.
+	if c.MinEdges > 0 {
+		maxEdges := g.M()
+		for j := k; j < n; j++ {
+			if c.MaxDegree > 0 && c.MaxDegree < j {
+				maxEdges += c.MaxDegree
+			} else {
+				maxEdges += j
+			}
+		}
+		if maxEdges < c.MinEdges {
+			return true
+		}
+	}
+
+	//Any new triangle or 4-cycle must contain v and two of its neighbours.
+	if c.TriangleFree || c.C4Free {
+		for i, u := range neighbours {
+			for _, w := range neighbours[:i] {
+				if c.TriangleFree && g.IsEdge(u, w) {
+					return true
+				}
+				if c.C4Free {
+					for x := 0; x < v; x++ {
+						if x != u && x != w && g.IsEdge(u, x) && g.IsEdge(w, x) {
+							return true
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if c.Bipartite && !isBipartite(g) {
+		return true
+	}
+	return false
+}
+
+//prune returns true if g has n vertices and doesn't satisfy the constraints which weren't checked by preprune.
+func (c Constraints) prune(g *graph.DenseGraph, n int) bool {
+	if g.N() != n {
+		return false
+	}
+	for _, d := range g.DegreeSequence {
+		if d < c.MinDegree {
+			return true
+		}
+	}
+	if g.M() < c.MinEdges {
+		return true
+	}
+	if (c.Connected || c.Biconnected) && len(graph.ConnectedComponents(g)) != 1 {
+		return true
+	}
+	if c.Biconnected {
+		if n < 3 {
+			return true
+		}
+		if _, cutVertices := graph.BiconnectedComponents(g); len(cutVertices) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//isBipartite returns true if g is bipartite by trying to 2-colour each component.
+func isBipartite(g *graph.DenseGraph) bool {
+	n := g.N()
+	colour := make([]int, n)
+	for i := range colour {
+		colour[i] = -1
+	}
+	toCheck := make([]int, 0, n)
+	for s := 0; s < n; s++ {
+		if colour[s] != -1 {
+			continue
+		}
+		colour[s] = 0
+		toCheck = append(toCheck, s)
+		for len(toCheck) > 0 {
+			v := toCheck[len(toCheck)-1]
+			toCheck = toCheck[:len(toCheck)-1]
+			for u := 0; u < n; u++ {
+				if u == v || !g.IsEdge(u, v) {
+					continue
+				}
+				if colour[u] == -1 {
+					colour[u] = 1 - colour[v]
+					toCheck = append(toCheck, u)
+				} else if colour[u] == colour[v] {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,203 @@
+package search
+
+import (
+	"github.com/Tom-Johnston/mamba/disjoint"
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//DigraphKind is the type of digraph found by a DigraphIterator.
+type DigraphKind int
+
+const (
+	//Digraphs are directed graphs without loops in which each arc appears at most once. Both of the arcs uv and vu may be present.
+	Digraphs DigraphKind = iota
+	//OrientedGraphs are digraphs in which at most one of the arcs uv and vu is present.
+	OrientedGraphs
+	//Tournaments are digraphs in which exactly one of the arcs uv and vu is present.
+	Tournaments
+)
+
+//relations returns the possible relations between a new vertex and an existing vertex for the kind of digraph. The relation 0 is no arc, 1 is an arc from the new vertex, 2 is an arc to the new vertex and 3 is both arcs.
+func (kind DigraphKind) relations() []int {
+	switch kind {
+	case Digraphs:
+		return []int{0, 1, 2, 3}
+	case OrientedGraphs:
+		return []int{0, 1, 2}
+	case Tournaments:
+		return []int{1, 2}
+	}
+	panic("Unknown kind of digraph")
+}
+
+//DigraphIterator is an iterator which iterates over all non-isomorphic digraphs, oriented graphs or tournaments. It should be initialised with AllDigraphs.
+//A DigraphIterator is not safe for concurrent use by multiple goroutines.
+type DigraphIterator struct {
+	n         int
+	kind      DigraphKind
+	relations []int
+	first     bool
+
+	g *graph.DenseDigraph
+
+	//levels[i] holds the augmentations of the digraph on i + 1 vertices which haven't been tried yet.
+	levels [][]digraphAugmentation
+}
+
+//digraphAugmentation is a new vertex given by its out-neighbours and in-neighbours.
+type digraphAugmentation struct {
+	out []int
+	in  []int
+}
+
+//AllDigraphs returns a *DigraphIterator which iterates over all non-isomorphic digraphs of the given kind on n vertices.
+//The iterator uses a canonical deletion DFS like All. The vertices are added one at a time and a digraph is only accepted if the new vertex has the smallest (total degree, out-degree) and is in the same orbit as the first such vertex in the canonical order given by graph.CanonicalIsomorphDigraph.
+//This is practical for digraphs on up to about 6 vertices, oriented graphs on up to about 7 vertices and tournaments on up to about 10 vertices.
+func AllDigraphs(n int, kind DigraphKind) *DigraphIterator {
+	iter := new(DigraphIterator)
+	iter.n = n
+	iter.kind = kind
+	iter.relations = kind.relations()
+	iter.first = true
+	iter.levels = make([][]digraphAugmentation, 0, n)
+	return iter
+}
+
+//Next attempts to move the iterator to the next digraph, returning true if there is a next digraph and false if it has iterated over all digraphs.
+func (iter *DigraphIterator) Next() bool {
+	if iter.first {
+		iter.first = false
+		if iter.n == 0 {
+			iter.g = graph.NewDenseDigraph(0, nil)
+			return true
+		}
+		iter.g = graph.NewDenseDigraph(1, nil)
+		if iter.n == 1 {
+			return true
+		}
+		iter.levels = append(iter.levels, iter.augmentations())
+	} else if iter.n <= 1 {
+		return false
+	}
+
+	//The digraph has len(iter.levels) vertices if we have just moved deeper and len(iter.levels) + 1 vertices if the last augmentation on the top level has been applied.
+	for len(iter.levels) > 0 {
+		top := len(iter.levels) - 1
+		if iter.g.N() == top+2 {
+			iter.g.RemoveVertex(top + 1)
+		}
+		augs := iter.levels[top]
+		if len(augs) == 0 {
+			iter.levels = iter.levels[:top]
+			continue
+		}
+		aug := augs[len(augs)-1]
+		iter.levels[top] = augs[:len(augs)-1]
+
+		iter.g.AddVertex(aug.out, aug.in)
+		if !iter.isCanonical() {
+			continue
+		}
+		if iter.g.N() == iter.n {
+			return true
+		}
+		iter.levels = append(iter.levels, iter.augmentations())
+	}
+	return false
+}
+
+//Value returns the current value of the iterator. You must not modify the returned value.
+func (iter *DigraphIterator) Value() *graph.DenseDigraph {
+	return iter.g
+}
+
+//augmentations returns one augmentation of the current digraph from each orbit of the automorphism group.
+//An augmentation is encoded as a number whose digit v in base len(iter.relations) gives the relation between the new vertex and v.
+func (iter *DigraphIterator) augmentations() []digraphAugmentation {
+	k := iter.g.N()
+	b := len(iter.relations)
+	powers := make([]int, k+1)
+	powers[0] = 1
+	for i := 0; i < k; i++ {
+		powers[i+1] = powers[i] * b
+	}
+	numCodes := powers[k]
+
+	_, _, generators := graph.CanonicalIsomorphDigraph(iter.g, nil)
+	ds := disjoint.New(numCodes)
+	if len(generators) > 0 {
+		digits := make([]int, k)
+		buf := make([]int, numCodes)
+		for c := 0; c < numCodes; c++ {
+			x := c
+			for v := 0; v < k; v++ {
+				digits[v] = x % b
+				x /= b
+			}
+			for _, gen := range generators {
+				image := 0
+				for v := 0; v < k; v++ {
+					//The relation to v becomes the relation to gen[v].
+					image += digits[v] * powers[gen[v]]
+				}
+				ds.UnionBuffered(c, image, buf)
+			}
+		}
+	}
+
+	augs := make([]digraphAugmentation, 0)
+	for c := 0; c < numCodes; c++ {
+		if ds[c] >= 0 {
+			continue
+		}
+		aug := digraphAugmentation{out: make([]int, 0), in: make([]int, 0)}
+		x := c
+		for v := 0; v < k; v++ {
+			r := iter.relations[x%b]
+			x /= b
+			if r&1 != 0 {
+				aug.out = append(aug.out, v)
+			}
+			if r&2 != 0 {
+				aug.in = append(aug.in, v)
+			}
+		}
+		augs = append(augs, aug)
+	}
+	return augs
+}
+
+//isCanonical returns true if the last vertex of the current digraph is the canonical vertex to delete.
+func (iter *DigraphIterator) isCanonical() bool {
+	g := iter.g
+	k := g.N()
+	v := k - 1
+	invariant := func(u int) int {
+		return (g.OutDegreeSequence[u]+g.InDegreeSequence[u])*k + g.OutDegreeSequence[u]
+	}
+
+	x := invariant(v)
+	viable := make([]bool, k)
+	numViable := 0
+	for u := 0; u < v; u++ {
+		y := invariant(u)
+		if y < x {
+			return false
+		} else if y == x {
+			viable[u] = true
+			numViable++
+		}
+	}
+	if numViable == 0 {
+		return true
+	}
+	viable[v] = true
+
+	perm, orbits, _ := graph.CanonicalIsomorphDigraph(g, nil)
+	for _, u := range perm {
+		if viable[u] {
+			return orbits.Find(u) == orbits.Find(v)
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+func TestAllDigraphs(t *testing.T) {
+	tests := []struct {
+		kind      DigraphKind
+		truthData []int
+	}{
+		{Digraphs, []int{1, 1, 3, 16, 218, 9608}},
+		{OrientedGraphs, []int{1, 1, 2, 7, 42, 582, 21480}},
+		{Tournaments, []int{1, 1, 1, 2, 4, 12, 56, 456, 6880}},
+	}
+	for _, test := range tests {
+		numberFound := make([]int, len(test.truthData))
+		for n := range test.truthData {
+			seen := make(map[string]struct{})
+			iter := AllDigraphs(n, test.kind)
+			for iter.Next() {
+				g := iter.Value()
+				numberFound[n]++
+				if g.N() != n {
+					t.Fatalf("Wrong number of vertices. Found: %v Expected: %v", g.N(), n)
+				}
+				for i := 0; i < n; i++ {
+					for j := 0; j < i; j++ {
+						arcs := 0
+						if g.IsEdge(i, j) {
+							arcs++
+						}
+						if g.IsEdge(j, i) {
+							arcs++
+						}
+						if (test.kind == OrientedGraphs && arcs > 1) || (test.kind == Tournaments && arcs != 1) {
+							t.Fatalf("%v is the wrong kind of digraph", graph.Digraph6Encode(g))
+						}
+					}
+				}
+				perm, _, _ := graph.CanonicalIsomorphDigraph(g, nil)
+				s := graph.Digraph6Encode(g.InducedSubgraph(perm))
+				if _, ok := seen[s]; ok {
+					t.Fatalf("%v was found twice", s)
+				}
+				seen[s] = struct{}{}
+			}
+		}
+		if !ints.Equal(numberFound, test.truthData) {
+			t.Errorf("Wrong number of digraphs of kind %v. Found: %v Expected: %v", test.kind, numberFound, test.truthData)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package search
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//ParallelOptions contains the options for searching for graphs in parallel. The zero value (or a nil *ParallelOptions) searches for all graphs using one worker for each available CPU.
+type ParallelOptions struct {
+	Workers    int //The number of goroutines to use. If Workers is 0, runtime.GOMAXPROCS(0) workers are used.
+	Shards     int //The number of pieces the search is split into. The workers take the next shard as soon as they finish their current one so more shards give better load balancing. If Shards is 0, 64 shards are used for each worker.
+	SplitLevel int //The level at which the search is split which must be between 1 and n - 1. If SplitLevel is 0, the level ceil(2n/3) - 1 is used as in All.
+
+	Constraints Constraints                    //The constraints on the graphs. See WithConstraints.
+	Preprune    func(g *graph.DenseGraph) bool //Preprune is used as in WithPruning and can be nil. It must be safe to call concurrently.
+	Prune       func(g *graph.DenseGraph) bool //Prune is used as in WithPruning and can be nil. It must be safe to call concurrently.
+
+	Checkpoint         func(state *ParallelState) error //If Checkpoint is not nil, it is called every CheckpointInterval and once more when the search finishes with a snapshot of the search. The workers are paused between graphs until Checkpoint returns so the snapshot matches exactly the graphs which have been passed to f. If Checkpoint returns an error, the search stops and the error is returned by Parallel.
+	CheckpointInterval time.Duration                    //If CheckpointInterval is 0, Checkpoint is only called when the search finishes.
+	Resume             *ParallelState                   //If Resume is not nil, the search continues from the snapshot instead of starting from the beginning. The values of Shards, SplitLevel and Constraints are taken from the snapshot.
+}
+
+//withDefaults returns a copy of the options with the default values filled in or an error if the options aren't valid for graphs on n vertices.
+func (options *ParallelOptions) withDefaults(n int) (ParallelOptions, error) {
+	o := ParallelOptions{}
+	if options != nil {
+		o = *options
+	}
+	if o.Workers <= 0 {
+		o.Workers = runtime.GOMAXPROCS(0)
+	}
+	if o.Resume != nil {
+		if o.Resume.N != n {
+			return o, fmt.Errorf("The snapshot is for a different number of vertices. Found: %v Expected: %v", o.Resume.N, n)
+		}
+		o.Shards = o.Resume.Shards
+		o.SplitLevel = o.Resume.SplitLevel
+		o.Constraints = o.Resume.Constraints
+	}
+	if o.Shards <= 0 {
+		o.Shards = 64 * o.Workers
+	}
+	if o.SplitLevel == 0 {
+		o.SplitLevel = 2*(n+1)/3 - 1
+	} else if n > 1 && (o.SplitLevel < 1 || o.SplitLevel >= n) {
+		return o, fmt.Errorf("The split level must be between 1 and n - 1. Found: %v", o.SplitLevel)
+	}
+	return o, nil
+}
+
+//shard returns the *GraphIterator for the shard a of the search.
+func (options ParallelOptions) shard(n, a int) *GraphIterator {
+	iter := WithConstraints(n, a, options.Shards, options.Constraints, options.Preprune, options.Prune)
+	iter.splitLevel = options.SplitLevel
+	iter.globalSplit = true
+	return iter
+}
+
+//parallelSearch holds the state of a search run by Parallel.
+type parallelSearch struct {
+	n       int
+	options ParallelOptions
+	f       func(worker int, g *graph.DenseGraph)
+
+	//mu protects nextShard and resumed which hold the shards which haven't been given to a worker.
+	mu        sync.Mutex
+	nextShard int
+	resumed   []*GraphIterator
+
+	//The workers hold a read lock on pause while they find the next graph so a snapshot can be taken while holding the write lock.
+	pause   sync.RWMutex
+	current []*GraphIterator
+	counts  []int
+	found   int
+	err     error //err is the error returned by the checkpoint function. The workers stop once it is set.
+}
+
+//take returns the next shard which hasn't been started or nil if there are no shards left.
+func (p *parallelSearch) take() *GraphIterator {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.resumed) > 0 {
+		iter := p.resumed[len(p.resumed)-1]
+		p.resumed = p.resumed[:len(p.resumed)-1]
+		return iter
+	}
+	if p.nextShard < p.options.Shards {
+		iter := p.options.shard(p.n, p.nextShard)
+		p.nextShard++
+		return iter
+	}
+	return nil
+}
+
+//work runs the worker w until there are no shards left.
+func (p *parallelSearch) work(w int) {
+	checkpointing := p.options.Checkpoint != nil
+	for {
+		if checkpointing {
+			p.pause.RLock()
+			if p.err != nil {
+				p.pause.RUnlock()
+				return
+			}
+		}
+		if p.current[w] == nil {
+			p.current[w] = p.take()
+		}
+		iter := p.current[w]
+		if iter != nil {
+			if iter.Next() {
+				p.counts[w]++
+				if p.f != nil {
+					p.f(w, iter.Value())
+				}
+			} else {
+				p.current[w] = nil
+			}
+		}
+		if checkpointing {
+			p.pause.RUnlock()
+		}
+		if iter == nil {
+			return
+		}
+	}
+}
+
+//snapshot pauses the workers and passes the current state of the search to the checkpoint function. If this returns an error, it is recorded in p.err and returned.
+func (p *parallelSearch) snapshot() error {
+	p.pause.Lock()
+	defer p.pause.Unlock()
+	state := &ParallelState{
+		N:           p.n,
+		Shards:      p.options.Shards,
+		SplitLevel:  p.options.SplitLevel,
+		Constraints: p.options.Constraints,
+		NextShard:   p.nextShard,
+		Found:       p.found,
+	}
+	for _, c := range p.counts {
+		state.Found += c
+	}
+	iters := append(append([]*GraphIterator{}, p.resumed...), p.current...)
+	for _, iter := range iters {
+		if iter == nil {
+			continue
+		}
+		b := new(bytes.Buffer)
+		if err := iter.Save(b); err != nil {
+			//Writing to a bytes.Buffer can't fail.
+			panic(err)
+		}
+		state.Iterators = append(state.Iterators, b.Bytes())
+	}
+	p.err = p.options.Checkpoint(state)
+	return p.err
+}
+
+//Parallel searches for all non-isomorphic graphs on n vertices satisfying the options using a pool of goroutines and returns the number of graphs found by each worker. If options is nil, the default options are used.
+//The search is split into shards where the choices at the split level are numbered in the order they are found and the shard a contains the choices equal to a mod the number of shards. The shards are handed out to the workers as they become free.
+//If f is not nil, it is called on each graph along with the index of the worker which found it. The function f is called concurrently by different workers but never concurrently by the same worker. The graph must not be modified and is only valid until f returns.
+//When resuming from a snapshot, the counts only include the graphs found after the snapshot was taken.
+//An error is returned if the options or the snapshot aren't valid. If the checkpoint function returns an error, the workers stop and the error is returned along with the number of graphs found by each worker before they stopped.
+func Parallel(n int, options *ParallelOptions, f func(worker int, g *graph.DenseGraph)) (counts []int, err error) {
+	o, err := options.withDefaults(n)
+	if err != nil {
+		return nil, err
+	}
+	p := &parallelSearch{n: n, options: o, f: f, current: make([]*GraphIterator, o.Workers), counts: make([]int, o.Workers)}
+	if o.Resume != nil {
+		p.nextShard = o.Resume.NextShard
+		p.found = o.Resume.Found
+		for _, b := range o.Resume.Iterators {
+			iter, err := Load(bytes.NewReader(b), o.Preprune, o.Prune)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid iterator in the snapshot: %v", err)
+			}
+			p.resumed = append(p.resumed, iter)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(o.Workers)
+	for w := 0; w < o.Workers; w++ {
+		go func(w int) {
+			defer wg.Done()
+			p.work(w)
+		}(w)
+	}
+
+	if o.Checkpoint != nil {
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			if o.CheckpointInterval <= 0 {
+				<-done
+				return
+			}
+			ticker := time.NewTicker(o.CheckpointInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if p.snapshot() != nil {
+						return
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+		wg.Wait()
+		close(done)
+		<-stopped
+		if p.err != nil {
+			return p.counts, p.err
+		}
+		if err := p.snapshot(); err != nil {
+			return p.counts, err
+		}
+	} else {
+		wg.Wait()
+	}
+	return p.counts, nil
+}
+
+//ParallelChannel is like Parallel but it sends a copy of each graph on the first returned channel which is closed once the search is finished. The search blocks until the graphs are received so the channel must be drained.
+//Once the first channel is closed, the error returned by Parallel (which may be nil) is sent on the second channel.
+func ParallelChannel(n int, options *ParallelOptions) (<-chan *graph.DenseGraph, <-chan error) {
+	workers := runtime.GOMAXPROCS(0)
+	if options != nil && options.Workers > 0 {
+		workers = options.Workers
+	}
+	c := make(chan *graph.DenseGraph, workers)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := Parallel(n, options, func(worker int, g *graph.DenseGraph) {
+			c <- g.Copy().(*graph.DenseGraph)
+		})
+		close(c)
+		errc <- err
+	}()
+	return c, errc
+}
+
+//parallelStateVersion is the version of the format written by ParallelState.Save.
+const parallelStateVersion = 1
+
+//ParallelState is a snapshot of a search run by Parallel. It can be saved and loaded with Save and LoadParallelState and the search can be resumed by setting ParallelOptions.Resume.
+type ParallelState struct {
+	Version     int
+	N           int
+	Shards      int
+	SplitLevel  int
+	Constraints Constraints
+
+	NextShard int      //The shards NextShard, NextShard + 1, ..., Shards - 1 haven't been started.
+	Iterators [][]byte //The saved iterators of the shards which have been started but not finished.
+	Found     int      //The number of graphs found before the snapshot was taken.
+}
+
+//Save writes the snapshot to w using gob. Any error from writing to w is returned.
+func (state *ParallelState) Save(w io.Writer) error {
+	s := *state
+	s.Version = parallelStateVersion
+	return gob.NewEncoder(w).Encode(&s)
+}
+
+//LoadParallelState reads a snapshot written by Save from r. An error is returned if the snapshot can't be decoded or isn't valid.
+func LoadParallelState(r io.Reader) (*ParallelState, error) {
+	state := new(ParallelState)
+	if err := gob.NewDecoder(r).Decode(state); err != nil {
+		return nil, err
+	}
+	if state.Version != parallelStateVersion {
+		return nil, fmt.Errorf("Unsupported version. Found: %v Expected: %v", state.Version, parallelStateVersion)
+	}
+	if state.N < 0 || state.Shards < 1 || state.NextShard < 0 || state.NextShard > state.Shards || state.Found < 0 {
+		return nil, errors.New("Invalid snapshot")
+	}
+	if state.N > 1 && (state.SplitLevel < 1 || state.SplitLevel >= state.N) {
+		return nil, fmt.Errorf("Invalid split level: %v", state.SplitLevel)
+	}
+	//Check the iterators belong to this search.
+	for _, b := range state.Iterators {
+		s := new(save)
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(s); err != nil {
+			return nil, err
+		}
+		if err := s.validate(); err != nil {
+			return nil, err
+		}
+		if s.Version == 0 || s.N != state.N || s.M != state.Shards || s.A >= state.NextShard || s.SplitLevel != state.SplitLevel || !s.GlobalSplit || s.Constraints != state.Constraints {
+			return nil, errors.New("An iterator doesn't belong to the search")
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,213 @@
+package search
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+func TestParallel(t *testing.T) {
+	truthData := []int{1, 1, 2, 4, 11, 34, 156, 1044, 12346}
+	for n := 0; n < len(truthData); n++ {
+		for _, options := range []*ParallelOptions{nil, {Workers: 3, Shards: 7}, {Workers: 4, Shards: 1000, SplitLevel: n / 2}} {
+			counts, err := Parallel(n, options, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count := ints.Sum(counts); count != truthData[n] {
+				t.Errorf("Wrong number of graphs on %v vertices with options %+v. Found: %v Expected: %v", n, options, count, truthData[n])
+			}
+		}
+	}
+
+	//Check the workers share the work and the callback sees every graph.
+	seen := make([]map[string]bool, 4)
+	for i := range seen {
+		seen[i] = make(map[string]bool)
+	}
+	counts, err := Parallel(8, &ParallelOptions{Workers: 4}, func(worker int, g *graph.DenseGraph) {
+		seen[worker][graph.Graph6Encode(g)] = true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for w, count := range counts {
+		if count != len(seen[w]) {
+			t.Errorf("Worker %v found %v graphs but the callback saw %v", w, count, len(seen[w]))
+		}
+		total += count
+	}
+	if total != 12346 {
+		t.Errorf("Wrong number of graphs on 8 vertices. Found: %v Expected: 12346", total)
+	}
+
+	//Constraints are applied to each shard.
+	counts, err = Parallel(7, &ParallelOptions{Constraints: Constraints{Connected: true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := ints.Sum(counts); count != 853 {
+		t.Errorf("Wrong number of connected graphs on 7 vertices. Found: %v Expected: 853", count)
+	}
+
+	//Invalid options return an error.
+	if _, err := Parallel(7, &ParallelOptions{SplitLevel: 7}, nil); err == nil {
+		t.Error("Expected an error for an invalid split level")
+	}
+}
+
+func TestParallelChannel(t *testing.T) {
+	//The graphs should be distinct up to isomorphism.
+	unique := make(map[string]bool)
+	count := 0
+	graphs, errc := ParallelChannel(7, &ParallelOptions{Workers: 3, Shards: 50})
+	for g := range graphs {
+		unique[graph.Graph6Encode(g.InducedSubgraph(graph.CanonicalIsomorph(g)))] = true
+		count++
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1044 || len(unique) != 1044 {
+		t.Errorf("Found %v graphs and %v up to isomorphism. Expected: 1044", count, len(unique))
+	}
+
+	//Errors are sent after the graphs.
+	graphs, errc = ParallelChannel(7, &ParallelOptions{SplitLevel: 9})
+	for range graphs {
+		t.Fatal("Found a graph in a search with invalid options")
+	}
+	if err := <-errc; err == nil {
+		t.Error("Expected an error for an invalid split level")
+	}
+}
+
+func TestParallelCheckpoint(t *testing.T) {
+	n := 7
+	options := &ParallelOptions{Workers: 3, Shards: 40, Constraints: Constraints{MaxDegree: 4}}
+
+	//Record every snapshot along with the graphs found before it.
+	var mu sync.Mutex
+	found := make(map[string]bool)
+	var snapshots [][]byte
+	var foundAtSnapshot []map[string]bool
+	options.CheckpointInterval = time.Millisecond
+	options.Checkpoint = func(state *ParallelState) error {
+		b := new(bytes.Buffer)
+		if err := state.Save(b); err != nil {
+			return err
+		}
+		snapshots = append(snapshots, b.Bytes())
+		copyOfFound := make(map[string]bool)
+		for k := range found {
+			copyOfFound[k] = true
+		}
+		foundAtSnapshot = append(foundAtSnapshot, copyOfFound)
+		return nil
+	}
+	_, err := Parallel(n, options, func(worker int, g *graph.DenseGraph) {
+		time.Sleep(10 * time.Microsecond)
+		mu.Lock()
+		found[graph.Graph6Encode(g)] = true
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := len(found)
+
+	for i, b := range snapshots {
+		state, err := LoadParallelState(bytes.NewReader(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state.Found != len(foundAtSnapshot[i]) {
+			t.Fatalf("The snapshot %v says %v graphs were found but %v were found", i, state.Found, len(foundAtSnapshot[i]))
+		}
+		//Resuming should find exactly the remaining graphs.
+		seen := foundAtSnapshot[i]
+		counts, err := Parallel(n, &ParallelOptions{Workers: 2, Resume: state}, func(worker int, g *graph.DenseGraph) {
+			mu.Lock()
+			defer mu.Unlock()
+			s := graph.Graph6Encode(g)
+			if seen[s] {
+				t.Errorf("Found %v again after resuming from snapshot %v", s, i)
+			}
+			seen[s] = true
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(seen) != expected || state.Found+ints.Sum(counts) != expected {
+			t.Fatalf("Found %v graphs after resuming from snapshot %v. Expected: %v", len(seen), i, expected)
+		}
+	}
+	if len(snapshots) < 2 {
+		t.Logf("Only %v snapshots were taken", len(snapshots))
+	}
+
+	//Invalid snapshots should return errors.
+	if _, err := LoadParallelState(bytes.NewReader([]byte("Not a snapshot"))); err == nil {
+		t.Error("Expected an error when loading an invalid snapshot")
+	}
+	state := &ParallelState{N: 7, Shards: 2, SplitLevel: 8}
+	b := new(bytes.Buffer)
+	state.Save(b)
+	if _, err := LoadParallelState(b); err == nil {
+		t.Error("Expected an error when loading a snapshot with an invalid split level")
+	}
+}
+
+func TestParallelErrors(t *testing.T) {
+	//An error from the checkpoint function stops the search and is returned.
+	errFull := errors.New("The disk is full")
+	calls := 0
+	options := &ParallelOptions{Workers: 2, CheckpointInterval: time.Millisecond}
+	options.Checkpoint = func(state *ParallelState) error {
+		calls++
+		return errFull
+	}
+	counts, err := Parallel(8, options, func(worker int, g *graph.DenseGraph) {
+		time.Sleep(10 * time.Microsecond)
+	})
+	if err != errFull {
+		t.Fatalf("Wrong error. Found: %v Expected: %v", err, errFull)
+	}
+	if calls != 1 || ints.Sum(counts) >= 12346 {
+		t.Errorf("The search continued after the checkpoint failed. Found %v graphs and %v checkpoints", ints.Sum(counts), calls)
+	}
+
+	//Take a snapshot part of the way through a search.
+	var state *ParallelState
+	options = &ParallelOptions{Workers: 2, Shards: 20, CheckpointInterval: time.Millisecond}
+	options.Checkpoint = func(s *ParallelState) error {
+		if state == nil && len(s.Iterators) > 0 {
+			state = s
+		}
+		return nil
+	}
+	if _, err := Parallel(7, options, func(worker int, g *graph.DenseGraph) {
+		time.Sleep(10 * time.Microsecond)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if state == nil {
+		t.Fatal("No snapshot was taken part of the way through the search")
+	}
+
+	//Resuming with the wrong number of vertices or a corrupt iterator returns an error.
+	if _, err := Parallel(8, &ParallelOptions{Resume: state}, nil); err == nil {
+		t.Error("Expected an error when resuming a snapshot for 7 vertices with 8 vertices")
+	}
+	corrupt := *state
+	corrupt.Iterators = append([][]byte{[]byte("Not an iterator")}, state.Iterators[1:]...)
+	if _, err := Parallel(7, &ParallelOptions{Resume: &corrupt}, nil); err == nil {
+		t.Error("Expected an error when resuming a snapshot with a corrupt iterator")
+	}
+}
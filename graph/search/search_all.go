@@ -2,6 +2,8 @@ package search
 
 import (
 	"encoding/gob"
+	"errors"
+	"fmt"
 	"io"
 	"math/bits"
 
@@ -12,8 +14,8 @@ import (
 	"github.com/Tom-Johnston/mamba/itertools"
 )
 
-//GraphIterator is an iterator which iterates over all non-isomorphic graphs. It should be initialised with either All or WithPruning.
-//The current state of the iterator can be saved with Save() and then this can be loaded again using Resume().
+//GraphIterator is an iterator which iterates over all non-isomorphic graphs. It should be initialised with All, WithPruning or WithConstraints.
+//The current state of the iterator can be saved with Save() and then this can be loaded again using Load().
 //A GraphIterator is not safe for concurrent use by multiple goroutines.
 type GraphIterator struct {
 	n        int
@@ -23,7 +25,12 @@ type GraphIterator struct {
 	preprune func(g *graph.DenseGraph) bool
 	prune    func(g *graph.DenseGraph) bool
 
+	constraints Constraints
+
 	splitLevel int
+	//If globalSplit is true, the choices at the split level are numbered in the order they are found across the whole search instead of only among the children of the same graph. This gives a much finer split for large m.
+	globalSplit  bool
+	splitCounter int
 
 	sg *searchGraph
 
@@ -157,8 +164,15 @@ func (iter *GraphIterator) Next() bool {
 				x := iter.choices[len(iter.choices)-1]
 				iter.choices = iter.choices[:len(iter.choices)-1]
 
-				if i%iter.m != iter.a && level == iter.splitLevel {
-					continue
+				if level == iter.splitLevel {
+					index := i
+					if iter.globalSplit {
+						index = iter.splitCounter
+						iter.splitCounter++
+					}
+					if index%iter.m != iter.a {
+						continue
+					}
 				}
 
 				iter.v = iter.v[:0]
@@ -212,8 +226,13 @@ func (iter *GraphIterator) Value() *graph.DenseGraph {
 	return iter.sg.G
 }
 
+//checkpointVersion is the version of the format written by Save. Version 0 is the original format which doesn't contain a version, the constraints or the details of the split.
+const checkpointVersion = 1
+
 //save stores the state of a GraphIterator needed to load the iterator later.
 type save struct {
+	Version int
+
 	N     int
 	A     int
 	M     int
@@ -223,13 +242,19 @@ type save struct {
 
 	Choices     []uint
 	CurrentPath []int
+
+	Constraints  Constraints
+	SplitLevel   int
+	GlobalSplit  bool
+	SplitCounter int
 }
 
-//Save writes the current state of the iterator to w so that it can be loaded using Load. This doesn't save the functions preprune and prune which will need to be supplied to Load on loading.
-//This cannot be called concurrently with Next and can only be called between graphs.
-func (iter *GraphIterator) Save(w io.Writer) {
+//Save writes the current state of the iterator to w so that it can be loaded using Load. The state includes the constraints but it doesn't save the functions preprune and prune which will need to be supplied to Load on loading.
+//This cannot be called concurrently with Next and can only be called between graphs. Any error from writing to w is returned.
+func (iter *GraphIterator) Save(w io.Writer) error {
 	//Create and fill a save struct.
 	s := new(save)
+	s.Version = checkpointVersion
 	s.N = iter.n
 	s.A = iter.a
 	s.M = iter.m
@@ -237,33 +262,100 @@ func (iter *GraphIterator) Save(w io.Writer) {
 	s.G = iter.sg.G
 	s.Choices = iter.choices
 	s.CurrentPath = iter.currentPath
+	s.Constraints = iter.constraints
+	s.SplitLevel = iter.splitLevel
+	s.GlobalSplit = iter.globalSplit
+	s.SplitCounter = iter.splitCounter
 
 	//Encode the save struct using gob.
 	enc := gob.NewEncoder(w)
-	err := enc.Encode(s)
-	if err != nil {
-		panic(err)
-	}
+	return enc.Encode(s)
 }
 
-//Load creates a new *GraphIterator from the saved information in r.
-func Load(r io.Reader, preprune, prune func(g *graph.DenseGraph) bool) *GraphIterator {
+//Load creates a new *GraphIterator from the saved information in r. The constraints are restored from the saved information and preprune and prune are used as in WithConstraints so they can be nil.
+//An error is returned if the saved information can't be decoded or isn't a valid state of an iterator.
+func Load(r io.Reader, preprune, prune func(g *graph.DenseGraph) bool) (*GraphIterator, error) {
 	//Decode the saved state into s.
 	s := new(save)
 	dec := gob.NewDecoder(r)
-	err := dec.Decode(s)
-	if err != nil {
-		panic(err)
+	if err := dec.Decode(s); err != nil {
+		return nil, err
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
 	}
+	return s.iterator(preprune, prune), nil
+}
 
+//validate checks that s is a valid state of an iterator.
+func (s *save) validate() error {
+	if s.Version < 0 || s.Version > checkpointVersion {
+		return fmt.Errorf("Unsupported version. Found: %v Expected: at most %v", s.Version, checkpointVersion)
+	}
+	if s.N < 0 || s.N > bits.UintSize {
+		return fmt.Errorf("Invalid number of vertices: %v", s.N)
+	}
+	if s.M < 1 || s.A < 0 {
+		return fmt.Errorf("Invalid split. a: %v m: %v", s.A, s.M)
+	}
+	if s.Version > 0 && s.N > 1 && (s.SplitLevel < 1 || s.SplitLevel >= s.N) {
+		return fmt.Errorf("Invalid split level: %v", s.SplitLevel)
+	}
+	if s.G == nil {
+		return errors.New("Missing graph")
+	}
+	k := s.G.NumberOfVertices
+	if k < 0 || k > s.N || len(s.G.DegreeSequence) != k || len(s.G.Edges) != (k*(k-1))/2 {
+		return errors.New("The graph has the wrong size")
+	}
+	degrees := make([]int, k)
+	m := 0
+	index := 0
+	for j := 0; j < k; j++ {
+		for i := 0; i < j; i++ {
+			if s.G.Edges[index] > 0 {
+				degrees[i]++
+				degrees[j]++
+				m++
+			}
+			index++
+		}
+	}
+	if m != s.G.NumberOfEdges {
+		return errors.New("The number of edges of the graph doesn't match the edges")
+	}
+	for i, d := range degrees {
+		if d != s.G.DegreeSequence[i] {
+			return errors.New("The degrees of the graph don't match the edges")
+		}
+	}
+	if len(s.CurrentPath) > s.N {
+		return errors.New("The path is too long")
+	}
+	for _, x := range s.Choices {
+		if s.N < bits.UintSize && x>>uint(s.N) != 0 {
+			return fmt.Errorf("Invalid choice: %v", x)
+		}
+	}
+	return nil
+}
+
+//iterator creates the *GraphIterator with the state s.
+func (s *save) iterator(preprune, prune func(g *graph.DenseGraph) bool) *GraphIterator {
 	//Create a new iterator in the starting state.
-	iter := WithPruning(s.N, s.A, s.M, preprune, prune)
+	iter := WithConstraints(s.N, s.A, s.M, s.Constraints, preprune, prune)
 	//Overwrite the relevant parts of the starting state.
 	iter.first = s.First
 
 	iter.choices = s.Choices
 	iter.currentPath = s.CurrentPath
 
+	if s.Version > 0 {
+		iter.splitLevel = s.SplitLevel
+		iter.globalSplit = s.GlobalSplit
+		iter.splitCounter = s.SplitCounter
+	}
+
 	//Note that the graph s.G might not be correctly sized so we will copy into the allocation.
 	iter.sg.G.NumberOfVertices = s.G.NumberOfVertices
 	iter.sg.G.NumberOfEdges = s.G.NumberOfEdges
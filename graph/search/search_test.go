@@ -2,6 +2,7 @@ package search
 
 import (
 	"bytes"
+	"encoding/gob"
 	"testing"
 
 	"github.com/Tom-Johnston/mamba/graph"
@@ -55,7 +56,10 @@ func TestSaveAndLoad(t *testing.T) {
 	initial := new(bytes.Buffer)
 	iter.Save(initial)
 
-	load := Load(initial, f, f)
+	load, err := Load(initial, f, f)
+	if err != nil {
+		t.Fatal(err)
+	}
 	count := 0
 	for load.Next() {
 		count++
@@ -74,7 +78,10 @@ func TestSaveAndLoad(t *testing.T) {
 	//Let's increment the state once and see if this throws it off.
 	iter.Next()
 
-	load = Load(after100, f, f)
+	load, err = Load(after100, f, f)
+	if err != nil {
+		t.Fatal(err)
+	}
 	count = 0
 	for load.Next() {
 		count++
@@ -89,7 +96,10 @@ func TestSaveAndLoad(t *testing.T) {
 	//Try saving after the iterator is finished.
 	end := new(bytes.Buffer)
 	iter.Save(end)
-	load = Load(end, f, f)
+	load, err = Load(end, f, f)
+	if err != nil {
+		t.Fatal(err)
+	}
 	count = 0
 	for load.Next() {
 		count++
@@ -112,8 +122,14 @@ func TestSaveAndLoad(t *testing.T) {
 	iter0.Save(save0)
 	iter1.Save(save1)
 
-	iter0 = Load(save0, f, f)
-	iter1 = Load(save1, f, f)
+	iter0, err = Load(save0, f, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	iter1, err = Load(save1, f, f)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	for iter0.Next() {
 		count++
@@ -135,3 +151,159 @@ func BenchmarkAll(b *testing.B) {
 		}
 	}
 }
+
+func TestWithConstraints(t *testing.T) {
+	tests := []struct {
+		constraints Constraints
+		truthData   []int
+	}{
+		{Constraints{Connected: true}, []int{1, 1, 2, 6, 21, 112, 853, 11117}},
+		{Constraints{Biconnected: true}, []int{0, 0, 1, 3, 10, 56, 468, 7123}},
+		{Constraints{TriangleFree: true}, []int{1, 2, 3, 7, 14, 38, 107, 410}},
+		{Constraints{C4Free: true}, []int{1, 2, 4, 8, 18, 44, 117, 351}},
+		{Constraints{TriangleFree: true, C4Free: true}, []int{1, 2, 3, 6, 11, 23, 48, 114}},
+		{Constraints{Bipartite: true}, []int{1, 2, 3, 7, 13, 35, 88, 303}},
+		{Constraints{Connected: true, MaxDegree: 3}, []int{1, 1, 2, 6, 10, 29, 64, 194}},
+		{Constraints{MinDegree: 3, MaxDegree: 3}, []int{0, 0, 0, 1, 0, 2, 0, 6}},
+		{Constraints{MinEdges: 3, MaxEdges: 3}, []int{0, 0, 1, 3, 4, 5, 5, 5}},
+		{Constraints{Connected: true, MinDegree: 2}, []int{0, 0, 1, 3, 11, 61, 507, 7442}},
+	}
+	for _, test := range tests {
+		for n := 1; n <= len(test.truthData); n++ {
+			iter := WithConstraints(n, 0, 1, test.constraints, nil, nil)
+			count := 0
+			for iter.Next() {
+				count++
+			}
+			if count != test.truthData[n-1] {
+				t.Errorf("Wrong number of graphs on %v vertices for %+v. Found: %v Expected: %v", n, test.constraints, count, test.truthData[n-1])
+			}
+		}
+	}
+}
+
+//satisfies checks the constraints directly on a graph on n vertices.
+func satisfies(g *graph.DenseGraph, c Constraints) bool {
+	n := g.N()
+	degrees := g.Degrees()
+	if ints.Min(degrees) < c.MinDegree || (c.MaxDegree > 0 && ints.Max(degrees) > c.MaxDegree) {
+		return false
+	}
+	if g.M() < c.MinEdges || (c.MaxEdges > 0 && g.M() > c.MaxEdges) {
+		return false
+	}
+	if (c.Connected || c.Biconnected) && len(graph.ConnectedComponents(g)) != 1 {
+		return false
+	}
+	if c.Biconnected {
+		for v := 0; v < n; v++ {
+			vertices := make([]int, 0, n-1)
+			for u := 0; u < n; u++ {
+				if u != v {
+					vertices = append(vertices, u)
+				}
+			}
+			if n < 3 || len(graph.ConnectedComponents(graph.InducedSubgraph(g, vertices))) != 1 {
+				return false
+			}
+		}
+	}
+	if ok, _ := graph.IsKColorable(g, 2); c.Bipartite && !ok {
+		return false
+	}
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			for d := 0; d < n; d++ {
+				if a == b || b == d || a == d || !g.IsEdge(a, b) || !g.IsEdge(b, d) {
+					continue
+				}
+				if c.TriangleFree && g.IsEdge(a, d) {
+					return false
+				}
+				for e := 0; e < n; e++ {
+					if c.C4Free && e != a && e != b && e != d && g.IsEdge(d, e) && g.IsEdge(e, a) {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+func TestWithConstraintsFiltering(t *testing.T) {
+	tests := []Constraints{
+		{Connected: true, MinDegree: 2, MaxDegree: 4},
+		{Biconnected: true, MaxEdges: 9},
+		{TriangleFree: true, MinEdges: 5},
+		{C4Free: true, Connected: true},
+		{Bipartite: true, MinDegree: 1, MaxEdges: 8},
+		{MinDegree: 3, MinEdges: 12, MaxEdges: 14},
+	}
+	n := 7
+	for _, c := range tests {
+		expected := 0
+		iter := All(n, 0, 1)
+		for iter.Next() {
+			if satisfies(iter.Value(), c) {
+				expected++
+			}
+		}
+		count := 0
+		iter = WithConstraints(n, 0, 1, c, nil, nil)
+		for iter.Next() {
+			if !satisfies(iter.Value(), c) {
+				t.Fatalf("%v doesn't satisfy %+v", graph.Graph6Encode(iter.Value()), c)
+			}
+			count++
+		}
+		if count != expected {
+			t.Errorf("Wrong number of graphs for %+v. Found: %v Expected: %v", c, count, expected)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	//The constraints should be restored.
+	iter := WithConstraints(7, 0, 1, Constraints{Connected: true}, nil, nil)
+	for i := 0; i < 100; i++ {
+		iter.Next()
+	}
+	b := new(bytes.Buffer)
+	if err := iter.Save(b); err != nil {
+		t.Fatal(err)
+	}
+	load, err := Load(b, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 100
+	for load.Next() {
+		count++
+	}
+	if count != 853 {
+		t.Errorf("Wrong number of connected graphs after loading. Found: %v Expected: 853", count)
+	}
+
+	//Garbage and invalid states should give errors.
+	if _, err := Load(bytes.NewReader([]byte("Not a save")), nil, nil); err == nil {
+		t.Error("Expected an error when loading garbage")
+	}
+	invalid := []save{
+		{Version: checkpointVersion + 1, N: 5, M: 1, SplitLevel: 3, G: graph.NewDense(0, nil)},
+		{Version: checkpointVersion, N: 5, M: 0, SplitLevel: 3, G: graph.NewDense(0, nil)},
+		{Version: checkpointVersion, N: 5, M: 1, SplitLevel: 3},
+		{Version: checkpointVersion, N: 5, M: 1, SplitLevel: 3, G: graph.NewDense(6, nil)},
+		{Version: checkpointVersion, N: 5, M: 1, SplitLevel: 3, G: &graph.DenseGraph{NumberOfVertices: 2, NumberOfEdges: 1, DegreeSequence: []int{1, 0}, Edges: []byte{1}}},
+		{Version: checkpointVersion, N: 5, M: 1, SplitLevel: 3, G: graph.NewDense(3, nil), Choices: []uint{1 << 6}},
+	}
+	for i, s := range invalid {
+		b := new(bytes.Buffer)
+		if err := gob.NewEncoder(b).Encode(&s); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(b, nil, nil); err == nil {
+			t.Errorf("Expected an error when loading invalid state %v", i)
+		}
+	}
+}
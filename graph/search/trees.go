@@ -0,0 +1,286 @@
+package search
+
+import (
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/itertools"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//This file contains iterators over rooted trees, free trees and forests. The trees are stored as level sequences: the vertices are listed in the order of a depth-first search from the root and level[i] is the distance from the root to the vertex i. The parent of the vertex i is the last vertex before i with level one less than level[i].
+//Rooted trees are generated by the algorithm of Beyer and Hedetniemi and free trees are generated by the algorithm of Wright, Richmond, Odlyzko and McKay which lists the trees rooted at their centre. The next rooted tree is found in constant amortised time and the free trees also need an O(n) check that the root is the canonical centre, so Value, which builds a new graph, is usually the slowest part.
+
+//TreeIterator is an iterator which iterates over all non-isomorphic rooted trees, free trees or forests on n vertices. It should be initialised with RootedTrees, FreeTrees or Forests.
+//A TreeIterator is not safe for concurrent use by multiple goroutines.
+type TreeIterator struct {
+	n     int
+	first bool
+	done  bool
+	next  func() bool
+
+	level []int
+
+	//The parts of the current partition of n and the start of each component in level for Forests.
+	partitions *itertools.IntegerPartitionIterator
+	parts      []int
+	starts     []int
+}
+
+//RootedTrees returns a *TreeIterator which iterates over all non-isomorphic rooted trees on n vertices. The root of each tree is the vertex 0.
+func RootedTrees(n int) *TreeIterator {
+	iter := &TreeIterator{n: n, first: true}
+	iter.next = iter.nextRootedTree
+	return iter
+}
+
+//FreeTrees returns a *TreeIterator which iterates over all non-isomorphic trees on n vertices. The vertex 0 is a centre of each tree. There is a single tree on 0 vertices which is the empty graph.
+func FreeTrees(n int) *TreeIterator {
+	iter := &TreeIterator{n: n, first: true}
+	iter.next = iter.nextFreeTree
+	return iter
+}
+
+//Forests returns a *TreeIterator which iterates over all non-isomorphic forests on n vertices. The components of each forest are listed in decreasing order of size and each component is a tree as found by FreeTrees.
+func Forests(n int) *TreeIterator {
+	iter := &TreeIterator{n: n, first: true}
+	iter.next = iter.nextForest
+	return iter
+}
+
+//Next attempts to move the iterator to the next tree or forest, returning true if there is one and false if it has iterated over all of them.
+func (iter *TreeIterator) Next() bool {
+	if iter.done {
+		return false
+	}
+	if iter.next() {
+		return true
+	}
+	iter.done = true
+	return false
+}
+
+//LevelSequence returns the level sequence of the current tree. For forests, the level sequences of the components are concatenated so the roots of the components are the vertices with level 0.
+//You must not modify the returned value.
+func (iter *TreeIterator) LevelSequence() []int {
+	return iter.level
+}
+
+//Value returns the current tree or forest as a new *graph.SparseGraph.
+func (iter *TreeIterator) Value() *graph.SparseGraph {
+	n := len(iter.level)
+	neighbours := make([]sortints.SortedInts, n)
+	//last[l] is the last vertex found at level l.
+	last := make([]int, n)
+	for v, l := range iter.level {
+		last[l] = v
+		if l == 0 {
+			continue
+		}
+		u := last[l-1]
+		neighbours[u] = append(neighbours[u], v)
+		neighbours[v] = append(neighbours[v], u)
+	}
+	//The parent is always smaller than the children so the neighbours are already sorted.
+	return graph.NewSparse(n, neighbours)
+}
+
+//nextRootedTree moves to the next rooted tree.
+func (iter *TreeIterator) nextRootedTree() bool {
+	if iter.first {
+		iter.first = false
+		if iter.n == 0 {
+			return false
+		}
+		iter.level = make([]int, iter.n)
+		for i := range iter.level {
+			iter.level[i] = i
+		}
+		return true
+	}
+	return nextRootedLevelSequence(iter.level, -1)
+}
+
+//nextFreeTree moves to the next free tree.
+func (iter *TreeIterator) nextFreeTree() bool {
+	if iter.first {
+		iter.first = false
+		iter.level = make([]int, iter.n)
+		firstFreeLevelSequence(iter.level)
+		return true
+	}
+	return nextFreeLevelSequence(iter.level)
+}
+
+//nextForest moves to the next forest.
+func (iter *TreeIterator) nextForest() bool {
+	if iter.first {
+		iter.first = false
+		iter.level = make([]int, iter.n)
+		if iter.n == 0 {
+			return true
+		}
+		iter.partitions = itertools.IntegerPartitions(iter.n)
+		return iter.nextPartition()
+	}
+	if iter.n == 0 {
+		return false
+	}
+
+	//Move the last component which can be moved and reset the components after it. Components of the same size are kept in the order they are found by FreeTrees so each multiset of trees is only found once.
+	k := len(iter.parts)
+	for j := k - 1; j >= 0; j-- {
+		if !nextFreeLevelSequence(iter.component(j)) {
+			continue
+		}
+		for i := j + 1; i < k; i++ {
+			if iter.parts[i] == iter.parts[i-1] {
+				copy(iter.component(i), iter.component(i-1))
+			} else {
+				firstFreeLevelSequence(iter.component(i))
+			}
+		}
+		return true
+	}
+	return iter.nextPartition()
+}
+
+//nextPartition moves to the first forest whose component sizes are the next integer partition.
+func (iter *TreeIterator) nextPartition() bool {
+	if !iter.partitions.Next() {
+		return false
+	}
+	iter.parts = iter.partitions.Value()
+	iter.starts = iter.starts[:0]
+	start := 0
+	for i, p := range iter.parts {
+		iter.starts = append(iter.starts, start)
+		start += p
+		firstFreeLevelSequence(iter.component(i))
+	}
+	return true
+}
+
+//component returns the level sequence of the ith component of the current forest.
+func (iter *TreeIterator) component(i int) []int {
+	return iter.level[iter.starts[i] : iter.starts[i]+iter.parts[i]]
+}
+
+//nextRootedLevelSequence modifies level to be the next level sequence of a rooted tree in the order of Beyer and Hedetniemi, returning false if it is the last one.
+//The level sequence is changed from the position p where p is the last vertex with level at least 2 if p < 0.
+func nextRootedLevelSequence(level []int, p int) bool {
+	if p < 0 {
+		p = len(level) - 1
+		for p > 0 && level[p] <= 1 {
+			p--
+		}
+	}
+	if p <= 0 {
+		return false
+	}
+	q := p - 1
+	for level[q] != level[p]-1 {
+		q--
+	}
+	//The subtree rooted at q is repeated as many times as will fit.
+	for i := p; i < len(level); i++ {
+		level[i] = level[i-p+q]
+	}
+	return true
+}
+
+//firstFreeLevelSequence sets level to the level sequence of the path rooted at its centre which is the first free tree found by nextFreeLevelSequence.
+func firstFreeLevelSequence(level []int) {
+	n := len(level)
+	for i := 0; i <= n/2 && i < n; i++ {
+		level[i] = i
+	}
+	for i := n/2 + 1; i < n; i++ {
+		level[i] = i - n/2
+	}
+}
+
+//nextFreeLevelSequence modifies level to be the next level sequence of a free tree rooted at its centre, returning false if it is the last one.
+func nextFreeLevelSequence(level []int) bool {
+	if len(level) <= 2 {
+		return false
+	}
+	if !nextRootedLevelSequence(level, -1) {
+		return false
+	}
+	for {
+		m, valid := splitLevelSequence(level)
+		if valid {
+			return true
+		}
+		//Jump past the rooted trees where the first subtree of the root is too large.
+		p := m - 1
+		large := level[p] > 2
+		if !nextRootedLevelSequence(level, p) {
+			return false
+		}
+		if large {
+			m, _ = splitLevelSequence(level)
+			h := 0
+			for _, l := range level[1:m] {
+				if l-1 > h {
+					h = l - 1
+				}
+			}
+			//Replace the end of the sequence with a path of length h + 1 so the rest of the tree is at least as high as the first subtree.
+			n := len(level)
+			for i := 0; i <= h; i++ {
+				level[n-h-1+i] = i + 1
+			}
+		}
+	}
+}
+
+//splitLevelSequence splits the rooted tree into the first subtree of the root and the rest of the tree. It returns the start m of the second subtree of the root and whether the root is a centre of the tree and the tree is the canonical choice of the rooted trees.
+//The tree is valid if the rest of the tree is at least as high as the first subtree and, if they have the same height, the first subtree is at most the rest of the tree when compared as level sequences (with the rest rooted at 0).
+func splitLevelSequence(level []int) (int, bool) {
+	n := len(level)
+	m := n
+	for i := 2; i < n; i++ {
+		if level[i] == 1 {
+			m = i
+			break
+		}
+	}
+	leftHeight := 0
+	for _, l := range level[1:m] {
+		if l-1 > leftHeight {
+			leftHeight = l - 1
+		}
+	}
+	restHeight := 0
+	for _, l := range level[m:] {
+		if l > restHeight {
+			restHeight = l
+		}
+	}
+	if restHeight < leftHeight {
+		return m, false
+	}
+	if restHeight > leftHeight {
+		return m, true
+	}
+	//The first subtree is level[1:m] with 1 subtracted and the rest is 0 followed by level[m:].
+	leftSize := m - 1
+	restSize := n - m + 1
+	if leftSize > restSize {
+		return m, false
+	}
+	if leftSize < restSize {
+		return m, true
+	}
+	for i := 0; i < leftSize; i++ {
+		l := level[1+i] - 1
+		r := 0
+		if i > 0 {
+			r = level[m+i-1]
+		}
+		if l != r {
+			return m, l < r
+		}
+	}
+	return m, true
+}
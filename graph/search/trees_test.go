@@ -0,0 +1,79 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/ints"
+)
+
+func TestTrees(t *testing.T) {
+	tests := []struct {
+		name      string
+		iter      func(n int) *TreeIterator
+		truthData []int
+		rooted    bool
+		forest    bool
+	}{
+		{"free trees", FreeTrees, []int{1, 1, 1, 1, 2, 3, 6, 11, 23, 47, 106, 235, 551, 1301, 3159, 7741, 19320, 48629}, false, false},
+		{"rooted trees", RootedTrees, []int{0, 1, 1, 2, 4, 9, 20, 48, 115, 286, 719, 1842, 4766, 12486, 32973}, true, false},
+		{"forests", Forests, []int{1, 1, 2, 3, 6, 10, 20, 37, 76, 153, 329, 710, 1601, 3658, 8599, 20514}, false, true},
+	}
+	//The largest n for which the graphs are checked to be non-isomorphic.
+	maxChecked := 11
+	for _, test := range tests {
+		numberFound := make([]int, len(test.truthData))
+		for n := range test.truthData {
+			seen := make(map[string]struct{})
+			iter := test.iter(n)
+			for iter.Next() {
+				numberFound[n]++
+				if n > maxChecked {
+					continue
+				}
+				g := iter.Value()
+				if g.N() != n {
+					t.Fatalf("Wrong number of vertices. Found: %v Expected: %v", g.N(), n)
+				}
+				components := len(graph.ConnectedComponents(g))
+				if g.M() != n-components || (!test.forest && n > 0 && components != 1) {
+					t.Fatalf("%v is not a tree", graph.Graph6Encode(g))
+				}
+				//Put the root in its own vertex class for rooted trees.
+				var classes [][]int
+				if test.rooted {
+					classes = [][]int{{0}}
+					if n > 1 {
+						classes = append(classes, make([]int, 0, n-1))
+						for v := 1; v < n; v++ {
+							classes[1] = append(classes[1], v)
+						}
+					}
+				}
+				perm, _, _ := graph.CanonicalIsomorphFull(g, classes)
+				s := graph.Graph6Encode(g.InducedSubgraph(perm))
+				if _, ok := seen[s]; ok {
+					t.Fatalf("%v was found twice", s)
+				}
+				seen[s] = struct{}{}
+			}
+		}
+		if !ints.Equal(numberFound, test.truthData) {
+			t.Errorf("Wrong number of %v. Found: %v Expected: %v", test.name, numberFound, test.truthData)
+		}
+	}
+
+	//The trees are labelled trees which can be passed to the rest of package graph.
+	iter := FreeTrees(10)
+	for iter.Next() {
+		g := iter.Value()
+		h := graph.PruferDecode(graph.PruferEncode(g))
+		for i := 0; i < 10; i++ {
+			for j := 0; j < i; j++ {
+				if g.IsEdge(i, j) != h.IsEdge(i, j) {
+					t.Fatalf("The Prüfer code of %v doesn't give the same tree", graph.Graph6Encode(g))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//Format is a text format for graphs which can be read by a Reader or written by a Writer.
+type Format int
+
+const (
+	//FormatGraph6 is the Graph6 format. See Graph6Encode.
+	FormatGraph6 Format = iota
+	//FormatSparse6 is the Sparse6 format. See Sparse6Encode.
+	FormatSparse6
+	//FormatDigraph6 is the Digraph6 format. See Digraph6Encode.
+	FormatDigraph6
+	//FormatIncrementalSparse6 is the Sparse6 format where a graph may instead be given by an incremental encoding starting with ; which describes how it differs from the previous graph. See IncrementalSparse6Encode.
+	FormatIncrementalSparse6
+)
+
+//String returns the name of the format as used in the optional header e.g. graph6.
+func (f Format) String() string {
+	switch f {
+	case FormatGraph6:
+		return "graph6"
+	case FormatSparse6:
+		return "sparse6"
+	case FormatDigraph6:
+		return "digraph6"
+	case FormatIncrementalSparse6:
+		return "incremental sparse6"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+//header returns the optional header for the format e.g. >>graph6<<. The incremental Sparse6 format uses the same header as the Sparse6 format.
+func (f Format) header() string {
+	if f == FormatIncrementalSparse6 {
+		return FormatSparse6.header()
+	}
+	return ">>" + f.String() + "<<"
+}
+
+//ParseError is the error returned by a Reader when a line can't be decoded.
+type ParseError struct {
+	Line int   //The line number starting from 1.
+	Err  error //The error from decoding the line.
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+//Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+//Reader reads graphs one at a time from an io.Reader where each line contains a single graph in the Graph6, Sparse6 or Digraph6 format.
+//The format is detected separately for each line so a file may mix formats. Empty lines are skipped and any headers such as >>graph6<< are removed.
+//A line containing an incremental Sparse6 encoding (starting with ;) is applied to the most recent graph which was not a digraph.
+//A Reader should be used like an iterator:
+//	r := NewReader(f)
+//	for r.Next() {
+//		g := r.Value()
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+//The lines are read one at a time so arbitrarily large files can be read using little memory.
+type Reader struct {
+	r      *bufio.Reader
+	line   int
+	format Format
+	g      Graph
+	prev   Graph
+	d      Digraph
+	err    error
+	done   bool
+}
+
+//NewReader returns a new Reader which reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+//Next reads the next graph and returns true if it was successful. It returns false at the end of the input or if an error occurs and the error can be found by calling Err.
+func (r *Reader) Next() bool {
+	if r.done {
+		return false
+	}
+	for {
+		s, err := r.r.ReadString('\n')
+		if err != nil && err != io.EOF {
+			r.err = err
+			r.done = true
+			return false
+		}
+		if err == io.EOF && len(s) == 0 {
+			r.done = true
+			return false
+		}
+		r.line++
+		s = strings.TrimRight(s, "\r\n")
+		if len(s) == 0 {
+			if err == io.EOF {
+				r.done = true
+				return false
+			}
+			continue
+		}
+		if decodeErr := r.decode(s); decodeErr != nil {
+			r.err = &ParseError{Line: r.line, Err: decodeErr}
+			r.done = true
+			return false
+		}
+		return true
+	}
+}
+
+//decode detects the format of the line s and decodes it.
+func (r *Reader) decode(s string) error {
+	for _, f := range []Format{FormatGraph6, FormatSparse6, FormatDigraph6} {
+		s = strings.TrimPrefix(s, f.header())
+	}
+	r.g = nil
+	r.d = nil
+	var err error
+	switch {
+	case strings.HasPrefix(s, ":"):
+		r.format = FormatSparse6
+		r.g, err = Sparse6Decode(s)
+	case strings.HasPrefix(s, ";"):
+		r.format = FormatIncrementalSparse6
+		r.g, err = IncrementalSparse6Decode(r.prev, s)
+	case strings.HasPrefix(s, "&"):
+		r.format = FormatDigraph6
+		r.d, err = Digraph6Decode(s)
+	default:
+		r.format = FormatGraph6
+		if len(s) == 0 {
+			return errors.New("Missing graph after header")
+		}
+		r.g, err = Graph6Decode(s)
+	}
+	if err == nil && r.g != nil {
+		r.prev = r.g
+	}
+	return err
+}
+
+//Value returns the most recent graph read by Next. If the most recent graph was a digraph, Value returns nil and the digraph can be found using DigraphValue.
+//A Graph6 encoding gives a *DenseGraph and a Sparse6 or incremental Sparse6 encoding gives a *SparseGraph.
+//The graph is used to decode any following incremental Sparse6 encodings so it should be copied before being modified.
+func (r *Reader) Value() Graph {
+	return r.g
+}
+
+//DigraphValue returns the most recent digraph read by Next. If the most recent graph was not a digraph, DigraphValue returns nil.
+func (r *Reader) DigraphValue() Digraph {
+	return r.d
+}
+
+//Format returns the format of the most recent graph read by Next. The format is FormatIncrementalSparse6 only if the graph was given by an incremental encoding.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+//Line returns the line number of the most recent graph read by Next. The first line is 1.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+//Err returns the first error encountered by the Reader. Errors in decoding a line are of type *ParseError. Reaching the end of the input is not an error.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+//Writer writes graphs one per line to an io.Writer in a chosen format. The output is buffered and Flush must be called once all the graphs have been written.
+//If header is true, the first graph is preceded by the header for the format e.g. >>graph6<<.
+//When using FormatIncrementalSparse6, each graph is written using whichever of the incremental encoding relative to the previous graph and the normal Sparse6 encoding lists fewer edges.
+type Writer struct {
+	w           *bufio.Writer
+	format      Format
+	header      bool
+	wroteHeader bool
+	n           int      //The number of vertices in the previous graph.
+	prevEdges   [][2]int //The edges of the previous graph for FormatIncrementalSparse6 or nil if there is no previous graph.
+}
+
+//NewWriter returns a new Writer which writes graphs to w in the given format. If header is true, the output starts with the header for the format.
+func NewWriter(w io.Writer, format Format, header bool) *Writer {
+	return &Writer{w: bufio.NewWriter(w), format: format, header: header}
+}
+
+//Write writes the graph g on its own line. It returns an error if the Writer is using the Digraph6 format or if writing fails.
+func (w *Writer) Write(g Graph) error {
+	var s string
+	switch w.format {
+	case FormatGraph6:
+		s = Graph6Encode(g)
+	case FormatSparse6:
+		s = Sparse6Encode(g)
+	case FormatIncrementalSparse6:
+		edges := sparse6Edges(g)
+		if w.prevEdges != nil && w.n == g.N() {
+			s = incrementalSparse6Encode(g.N(), w.prevEdges, edges)
+		} else {
+			s = Sparse6Encode(g)
+		}
+		w.n = g.N()
+		w.prevEdges = edges
+	default:
+		return fmt.Errorf("Can't write a graph in the %v format", w.format)
+	}
+	return w.writeLine(s)
+}
+
+//WriteDigraph writes the digraph g on its own line. It returns an error if the Writer is not using the Digraph6 format or if writing fails.
+func (w *Writer) WriteDigraph(g Digraph) error {
+	if w.format != FormatDigraph6 {
+		return fmt.Errorf("Can't write a digraph in the %v format", w.format)
+	}
+	return w.writeLine(Digraph6Encode(g))
+}
+
+func (w *Writer) writeLine(s string) error {
+	if w.header && !w.wroteHeader {
+		if _, err := w.w.WriteString(w.format.header()); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	if _, err := w.w.WriteString(s); err != nil {
+		return err
+	}
+	return w.w.WriteByte('\n')
+}
+
+//Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
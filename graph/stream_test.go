@@ -0,0 +1,197 @@
+package graph_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/graph"
+	"github.com/Tom-Johnston/mamba/graph/search"
+)
+
+func TestReader(t *testing.T) {
+	input := ">>graph6<<DQc\r\n\n:K`ADOccQXK`IaXcQMb\n>>digraph6<<&DI?AO?\nDQc"
+	formats := []graph.Format{graph.FormatGraph6, graph.FormatSparse6, graph.FormatDigraph6, graph.FormatGraph6}
+	lines := []int{1, 3, 4, 5}
+	r := graph.NewReader(strings.NewReader(input))
+	count := 0
+	for r.Next() {
+		if count >= len(formats) {
+			t.Fatalf("Too many graphs")
+		}
+		if r.Format() != formats[count] {
+			t.Errorf("Wrong format for graph %v. Found: %v Expected: %v", count, r.Format(), formats[count])
+		}
+		if r.Line() != lines[count] {
+			t.Errorf("Wrong line for graph %v. Found: %v Expected: %v", count, r.Line(), lines[count])
+		}
+		switch r.Format() {
+		case graph.FormatGraph6:
+			if s := graph.Graph6Encode(r.Value()); s != "DQc" {
+				t.Errorf("Wrong graph. Found: %v Expected: DQc", s)
+			}
+		case graph.FormatSparse6:
+			if s := graph.Graph6Encode(r.Value()); s != "Ks@HOo?PGdCK" {
+				t.Errorf("Wrong graph. Found: %v Expected: Ks@HOo?PGdCK", s)
+			}
+		case graph.FormatDigraph6:
+			if r.Value() != nil {
+				t.Errorf("Value should be nil for a digraph")
+			}
+			if s := graph.Digraph6Encode(r.DigraphValue()); s != "&DI?AO?" {
+				t.Errorf("Wrong digraph. Found: %v Expected: &DI?AO?", s)
+			}
+		}
+		count++
+	}
+	if err := r.Err(); err != nil {
+		t.Error(err)
+	}
+	if count != len(formats) {
+		t.Errorf("Wrong number of graphs. Found: %v Expected: %v", count, len(formats))
+	}
+
+	//Errors should report the line.
+	r = graph.NewReader(strings.NewReader("DQc\nD!c\nDQc\n"))
+	count = 0
+	for r.Next() {
+		count++
+	}
+	err, ok := r.Err().(*graph.ParseError)
+	if !ok || err.Line != 2 || count != 1 {
+		t.Errorf("Expected an error on line 2 after 1 graph. Found: %v after %v graphs", r.Err(), count)
+	}
+
+	for _, s := range []string{":", "~", ":~?", "&", ">>graph6<<"} {
+		r = graph.NewReader(strings.NewReader(s))
+		if r.Next() || r.Err() == nil {
+			t.Errorf("Expected an error when reading %q", s)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	for _, format := range []graph.Format{graph.FormatGraph6, graph.FormatSparse6} {
+		var b bytes.Buffer
+		w := graph.NewWriter(&b, format, true)
+		var graphs []string
+		iter := search.All(6, 0, 1)
+		for iter.Next() {
+			g := iter.Value()
+			graphs = append(graphs, graph.Graph6Encode(g))
+			if err := w.Write(g); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.WriteDigraph(graph.NewDenseDigraph(2, nil)); err == nil {
+			t.Errorf("Expected an error when writing a digraph in the %v format", format)
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(b.String(), ">>"+format.String()+"<<") {
+			t.Errorf("Missing header for the %v format", format)
+		}
+
+		r := graph.NewReader(&b)
+		count := 0
+		for r.Next() {
+			if r.Format() != format {
+				t.Errorf("Wrong format. Found: %v Expected: %v", r.Format(), format)
+			}
+			if s := graph.Graph6Encode(r.Value()); s != graphs[count] {
+				t.Errorf("Wrong graph on line %v. Found: %v Expected: %v", r.Line(), s, graphs[count])
+			}
+			count++
+		}
+		if r.Err() != nil {
+			t.Error(r.Err())
+		}
+		if count != len(graphs) {
+			t.Errorf("Wrong number of graphs. Found: %v Expected: %v", count, len(graphs))
+		}
+	}
+
+	var b bytes.Buffer
+	w := graph.NewWriter(&b, graph.FormatDigraph6, false)
+	d := graph.NewDenseDigraph(3, nil)
+	d.AddEdge(0, 1)
+	d.AddEdge(2, 1)
+	if err := w.Write(graph.NewDense(2, nil)); err == nil {
+		t.Errorf("Expected an error when writing a graph in the digraph6 format")
+	}
+	if err := w.WriteDigraph(d); err != nil {
+		t.Fatal(err)
+	}
+	w.Flush()
+	if s := b.String(); s != graph.Digraph6Encode(d)+"\n" {
+		t.Errorf("Wrong output. Found: %q Expected: %q", s, graph.Digraph6Encode(d)+"\n")
+	}
+}
+
+func TestIncrementalSparse6(t *testing.T) {
+	var b bytes.Buffer
+	w := graph.NewWriter(&b, graph.FormatIncrementalSparse6, true)
+	var graphs []string
+	iter := search.All(7, 0, 1)
+	for iter.Next() {
+		g := iter.Value()
+		graphs = append(graphs, graph.Graph6Encode(g))
+		if err := w.Write(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.Flush()
+	if !strings.HasPrefix(b.String(), ">>sparse6<<:") {
+		t.Errorf("Expected the output to start with >>sparse6<<:")
+	}
+	if strings.Count(b.String(), "\n;") == 0 {
+		t.Errorf("No incremental encodings were used")
+	}
+
+	r := graph.NewReader(&b)
+	count := 0
+	for r.Next() {
+		if s := graph.Graph6Encode(r.Value()); s != graphs[count] {
+			t.Errorf("Wrong graph on line %v. Found: %v Expected: %v", r.Line(), s, graphs[count])
+		}
+		count++
+	}
+	if r.Err() != nil {
+		t.Error(r.Err())
+	}
+	if count != len(graphs) {
+		t.Errorf("Wrong number of graphs. Found: %v Expected: %v", count, len(graphs))
+	}
+
+	//The path 0 - 1 - 2 - 3 becomes the cycle by adding the edge 03 and then the complete graph by adding 02 and 13.
+	prev := graph.Path(4)
+	steps := []*graph.DenseGraph{graph.Cycle(4), graph.CompleteGraph(4)}
+	for _, g := range steps {
+		s := graph.IncrementalSparse6Encode(prev, g)
+		if s[0] != ';' {
+			t.Errorf("Expected an incremental encoding. Found: %v", s)
+		}
+		h, err := graph.IncrementalSparse6Decode(prev, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !graph.Equal(g, h) {
+			t.Errorf("Decoded %v as %v. Expected: %v", s, graph.Graph6Encode(h), graph.Graph6Encode(g))
+		}
+		prev = g
+	}
+
+	//Incremental encodings need a previous graph of the same size.
+	s := graph.IncrementalSparse6Encode(graph.Path(5), graph.Cycle(5))
+	if _, err := graph.IncrementalSparse6Decode(graph.Path(4), s); err == nil {
+		t.Errorf("Expected an error when the number of vertices differs")
+	}
+	if _, err := graph.IncrementalSparse6Decode(nil, s); err == nil {
+		t.Errorf("Expected an error when there is no previous graph")
+	}
+	r = graph.NewReader(strings.NewReader(s))
+	if r.Next() || r.Err() == nil {
+		t.Errorf("Expected an error when the stream starts with an incremental encoding")
+	}
+}
@@ -0,0 +1,383 @@
+package graph
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/Tom-Johnston/mamba/disjoint"
+	"github.com/Tom-Johnston/mamba/sortints"
+)
+
+//Tutte is the Tutte polynomial of a multigraph together with the number of vertices, edges and components of the multigraph which are needed for many of its specialisations.
+//Coefficients[i][j] is the coefficient of x^i y^j and there are n - c + 1 rows each of length m - n + c + 1 where n, m and c are the number of vertices, edges and components respectively.
+type Tutte struct {
+	Coefficients [][]*big.Int
+	N            int
+	M            int
+	Components   int
+}
+
+//TuttePolynomial returns the Tutte polynomial of g.
+//The Tutte polynomial is multiplicative over the blocks of g so g is split into blocks which are handled separately. Bridges, loops, parallel edges and cycles are recognised directly and the remaining blocks are split using deletion-contraction. Contracting edges creates multiple edges so these are handled as a single edge of higher multiplicity and the Tutte polynomials of the blocks are cached by their canonical isomorph as an edge-coloured graph where the colour of an edge is its multiplicity.
+//The running time is exponential in the number of edges and this is only suitable for small graphs.
+func TuttePolynomial(g Graph) *Tutte {
+	n := g.N()
+	tg := tutteMultigraph{n: n, mult: make([]int, n*n)}
+	for v := 0; v < n; v++ {
+		for _, u := range g.Neighbours(v) {
+			tg.mult[v*n+u] = 1
+		}
+	}
+	return newTutte(tg, 0, g.M())
+}
+
+//TuttePolynomialMultigraph returns the Tutte polynomial of the multigraph g. See TuttePolynomial for details.
+func TuttePolynomialMultigraph(g *Multigraph) *Tutte {
+	n := g.N()
+	tg := tutteMultigraph{n: n, mult: make([]int, n*n)}
+	loops := 0
+	for _, e := range g.Ends {
+		if e[0] == e[1] {
+			loops++
+			continue
+		}
+		tg.mult[e[0]*n+e[1]]++
+		tg.mult[e[1]*n+e[0]]++
+	}
+	return newTutte(tg, loops, g.M())
+}
+
+//newTutte returns the Tutte polynomial of the loopless multigraph g with the given number of loops added.
+func newTutte(g tutteMultigraph, loops, m int) *Tutte {
+	n := g.n
+	ds := disjoint.New(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if g.mult[i*n+j] > 0 {
+				ds.Union(i, j)
+			}
+		}
+	}
+	c := len(ds.Roots())
+
+	ts := &tutteState{cache: make(map[string][][]*big.Int)}
+	poly := ts.tutte(g)
+	coefficients := bivariateZero(n-c, m-n+c)
+	for i := range poly {
+		for j := range poly[i] {
+			if poly[i][j].Sign() != 0 {
+				coefficients[i][j+loops].Set(poly[i][j])
+			}
+		}
+	}
+	return &Tutte{Coefficients: coefficients, N: n, M: m, Components: c}
+}
+
+//Evaluate returns T(x, y).
+func (t *Tutte) Evaluate(x, y *big.Int) *big.Int {
+	value := new(big.Int)
+	row := new(big.Int)
+	for i := len(t.Coefficients) - 1; i >= 0; i-- {
+		row.SetInt64(0)
+		for j := len(t.Coefficients[i]) - 1; j >= 0; j-- {
+			row.Mul(row, y)
+			row.Add(row, t.Coefficients[i][j])
+		}
+		value.Mul(value, x)
+		value.Add(value, row)
+	}
+	return value
+}
+
+//NumberOfSpanningTrees returns the number of spanning trees which is T(1, 1) if the multigraph is connected and 0 otherwise.
+func (t *Tutte) NumberOfSpanningTrees() *big.Int {
+	if t.Components != 1 {
+		return new(big.Int)
+	}
+	return t.Evaluate(big.NewInt(1), big.NewInt(1))
+}
+
+//NumberOfSpanningForests returns the number of spanning forests (sets of edges which don't contain a cycle) which is T(2, 1).
+func (t *Tutte) NumberOfSpanningForests() *big.Int {
+	return t.Evaluate(big.NewInt(2), big.NewInt(1))
+}
+
+//NumberOfAcyclicOrientations returns the number of orientations of the edges which don't contain a directed cycle which is T(2, 0).
+func (t *Tutte) NumberOfAcyclicOrientations() *big.Int {
+	return t.Evaluate(big.NewInt(2), big.NewInt(0))
+}
+
+//ChromaticPolynomial returns the coefficients of the chromatic polynomial (-1)^(n - c) x^c T(1 - x, 0) starting with the constant term.
+//ChromaticPolynomial(g) is usually much quicker if only the chromatic polynomial is needed.
+func (t *Tutte) ChromaticPolynomial() []*big.Int {
+	a := make([]*big.Int, len(t.Coefficients))
+	for i := range a {
+		a[i] = t.Coefficients[i][0]
+	}
+	poly := composeOneMinus(a)
+	if (t.N-t.Components)%2 == 1 {
+		poly = polyNeg(poly)
+	}
+	for i := 0; i < t.Components; i++ {
+		poly = append([]*big.Int{new(big.Int)}, poly...)
+	}
+	return poly
+}
+
+//FlowPolynomial returns the coefficients of the flow polynomial (-1)^(m - n + c) T(0, 1 - x) starting with the constant term. The flow polynomial evaluated at k is the number of nowhere-zero flows with values in an abelian group of order k.
+func (t *Tutte) FlowPolynomial() []*big.Int {
+	poly := composeOneMinus(t.Coefficients[0])
+	if (t.M-t.N+t.Components)%2 == 1 {
+		poly = polyNeg(poly)
+	}
+	return poly
+}
+
+//ReliabilityPolynomial returns the coefficients of the all-terminal reliability polynomial p^(n - c) (1 - p)^(m - n + c) T(1, 1/(1 - p)) starting with the constant term. This is the probability that every component remains connected when each edge is kept independently with probability p.
+func (t *Tutte) ReliabilityPolynomial() []*big.Int {
+	nullity := t.M - t.N + t.Components
+	//Find the sum of T(1, y) evaluated at y = 1/(1 - p) multiplied by (1 - p)^nullity as a polynomial in 1 - p.
+	a := make([]*big.Int, nullity+1)
+	for j := range a {
+		a[nullity-j] = new(big.Int)
+		for i := range t.Coefficients {
+			a[nullity-j].Add(a[nullity-j], t.Coefficients[i][j])
+		}
+	}
+	poly := composeOneMinus(a)
+	for i := 0; i < t.N-t.Components; i++ {
+		poly = append([]*big.Int{new(big.Int)}, poly...)
+	}
+	return poly
+}
+
+//composeOneMinus returns the coefficients of the polynomial a(1 - x).
+func composeOneMinus(a []*big.Int) []*big.Int {
+	oneMinus := []*big.Int{big.NewInt(1), big.NewInt(-1)}
+	poly := []*big.Int{new(big.Int)}
+	for i := len(a) - 1; i >= 0; i-- {
+		poly = polyAdd(polyMul(poly, oneMinus), []*big.Int{a[i]})
+	}
+	return poly[:len(a)]
+}
+
+//tutteMultigraph is a multigraph without loops where mult[i*n + j] is the number of edges between i and j.
+type tutteMultigraph struct {
+	n    int
+	mult []int
+}
+
+//induced returns the multigraph induced by the vertices V.
+func (g tutteMultigraph) induced(V []int) tutteMultigraph {
+	h := tutteMultigraph{n: len(V), mult: make([]int, len(V)*len(V))}
+	for i, u := range V {
+		for j, v := range V {
+			h.mult[i*h.n+j] = g.mult[u*g.n+v]
+		}
+	}
+	return h
+}
+
+//contract returns the multigraph formed by contracting all the edges between u and v and removing the loops this creates.
+func (g tutteMultigraph) contract(u, v int) tutteMultigraph {
+	n := g.n
+	V := make([]int, 0, n-1)
+	for w := 0; w < n; w++ {
+		if w != v {
+			V = append(V, w)
+		}
+	}
+	h := g.induced(V)
+	if v < u {
+		u--
+	}
+	for i, w := range V {
+		if i != u {
+			h.mult[u*h.n+i] += g.mult[v*n+w]
+			h.mult[i*h.n+u] += g.mult[v*n+w]
+		}
+	}
+	return h
+}
+
+//tutteState holds the cache of the Tutte polynomials of 2-connected multigraphs.
+type tutteState struct {
+	cache map[string][][]*big.Int
+}
+
+//tutte returns the Tutte polynomial of g.
+func (ts *tutteState) tutte(g tutteMultigraph) [][]*big.Int {
+	n := g.n
+	neighbours := make([][]int, n)
+	m := 0
+	simple := true
+	for v := range neighbours {
+		for u := 0; u < n; u++ {
+			if g.mult[v*n+u] > 0 {
+				neighbours[v] = append(neighbours[v], u)
+				m++
+				if g.mult[v*n+u] > 1 {
+					simple = false
+				}
+			}
+		}
+	}
+	m /= 2
+
+	blocks := blocks(neighbours)
+	if len(blocks) == 0 {
+		return bivariateOne()
+	}
+	if len(blocks) > 1 || len(blocks[0]) < n {
+		poly := bivariateOne()
+		for _, b := range blocks {
+			poly = bivariateMul(poly, ts.tutte(g.induced(b)))
+		}
+		return poly
+	}
+
+	//g is 2-connected.
+	if n == 2 {
+		//The k parallel edges have Tutte polynomial x + y + y^2 + ... + y^(k - 1).
+		poly := bivariateZero(1, g.mult[1]-1)
+		poly[1][0].SetInt64(1)
+		for j := 1; j < g.mult[1]; j++ {
+			poly[0][j].SetInt64(1)
+		}
+		return poly
+	}
+	if simple && m == n {
+		//The cycle has Tutte polynomial x + x^2 + ... + x^(n - 1) + y.
+		poly := bivariateZero(n-1, 1)
+		for i := 1; i < n; i++ {
+			poly[i][0].SetInt64(1)
+		}
+		poly[0][1].SetInt64(1)
+		return poly
+	}
+
+	key := ts.canonicalKey(g, neighbours)
+	if p, ok := ts.cache[key]; ok {
+		return p
+	}
+
+	//Use T(G) = T(G - uv) + (1 + y + ... + y^(k - 1))T(G / uv) for the k edges between u and v where u has the smallest degree in the underlying graph.
+	u, v := 0, -1
+	for i := 1; i < n; i++ {
+		if len(neighbours[i]) < len(neighbours[u]) {
+			u = i
+		}
+	}
+	for _, i := range neighbours[u] {
+		if v == -1 || g.mult[u*n+i] > g.mult[u*n+v] || (g.mult[u*n+i] == g.mult[u*n+v] && len(neighbours[i]) > len(neighbours[v])) {
+			v = i
+		}
+	}
+	k := g.mult[u*n+v]
+	deleted := tutteMultigraph{n: n, mult: append([]int{}, g.mult...)}
+	deleted.mult[u*n+v] = 0
+	deleted.mult[v*n+u] = 0
+	p := bivariateAdd(ts.tutte(deleted), bivariateMul(bivariateGeometric(k), ts.tutte(g.contract(u, v))))
+	ts.cache[key] = p
+	return p
+}
+
+//canonicalKey returns a string which is the same for two multigraphs if and only if they are isomorphic.
+func (ts *tutteState) canonicalKey(g tutteMultigraph, neighbours [][]int) string {
+	n := g.n
+	neighbourhoods := make([]sortints.SortedInts, n)
+	for v := range neighbours {
+		neighbourhoods[v] = neighbours[v]
+	}
+	perm, _, _ := CanonicalIsomorphEdgeColoured(NewSparse(n, neighbourhoods), func(i, j int) int {
+		return g.mult[i*n+j]
+	}, nil)
+	key := make([]byte, 0, 1+n*(n-1)/2)
+	buf := make([]byte, binary.MaxVarintLen64)
+	key = append(key, buf[:binary.PutUvarint(buf, uint64(n))]...)
+	for i := 1; i < n; i++ {
+		for j := 0; j < i; j++ {
+			key = append(key, buf[:binary.PutUvarint(buf, uint64(g.mult[perm[i]*n+perm[j]]))]...)
+		}
+	}
+	return string(key)
+}
+
+//bivariateZero returns the zero polynomial with space for the coefficients of x^i y^j for i <= dx and j <= dy.
+func bivariateZero(dx, dy int) [][]*big.Int {
+	poly := make([][]*big.Int, dx+1)
+	for i := range poly {
+		poly[i] = make([]*big.Int, dy+1)
+		for j := range poly[i] {
+			poly[i][j] = new(big.Int)
+		}
+	}
+	return poly
+}
+
+//bivariateOne returns the polynomial 1.
+func bivariateOne() [][]*big.Int {
+	return [][]*big.Int{{big.NewInt(1)}}
+}
+
+//bivariateGeometric returns the polynomial 1 + y + y^2 + ... + y^(k - 1).
+func bivariateGeometric(k int) [][]*big.Int {
+	poly := bivariateZero(0, k-1)
+	for j := range poly[0] {
+		poly[0][j].SetInt64(1)
+	}
+	return poly
+}
+
+//bivariateAdd returns a + b.
+func bivariateAdd(a, b [][]*big.Int) [][]*big.Int {
+	dx, dy := 0, 0
+	for _, p := range [][][]*big.Int{a, b} {
+		if len(p)-1 > dx {
+			dx = len(p) - 1
+		}
+		for i := range p {
+			if len(p[i])-1 > dy {
+				dy = len(p[i]) - 1
+			}
+		}
+	}
+	sum := bivariateZero(dx, dy)
+	for _, p := range [][][]*big.Int{a, b} {
+		for i := range p {
+			for j := range p[i] {
+				sum[i][j].Add(sum[i][j], p[i][j])
+			}
+		}
+	}
+	return sum
+}
+
+//bivariateMul returns ab.
+func bivariateMul(a, b [][]*big.Int) [][]*big.Int {
+	dy := 0
+	for _, p := range [][][]*big.Int{a, b} {
+		max := 0
+		for i := range p {
+			if len(p[i])-1 > max {
+				max = len(p[i]) - 1
+			}
+		}
+		dy += max
+	}
+	product := bivariateZero(len(a)+len(b)-2, dy)
+	tmp := new(big.Int)
+	for i1 := range a {
+		for j1 := range a[i1] {
+			if a[i1][j1].Sign() == 0 {
+				continue
+			}
+			for i2 := range b {
+				for j2 := range b[i2] {
+					product[i1+i2][j1+j2].Add(product[i1+i2][j1+j2], tmp.Mul(a[i1][j1], b[i2][j2]))
+				}
+			}
+		}
+	}
+	return product
+}
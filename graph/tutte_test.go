@@ -0,0 +1,217 @@
+package graph_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Tom-Johnston/mamba/disjoint"
+	"github.com/Tom-Johnston/mamba/graph"
+)
+
+//subsetExpansion returns the Tutte polynomial of the multigraph on n vertices with the given edges using the sum over the subsets of the edges along with the number of spanning forests and the reliability polynomial.
+func subsetExpansion(n int, ends [][2]int) (tutte [][]int64, forests int64, reliability []int64) {
+	m := len(ends)
+	rank := func(subset int) int {
+		ds := disjoint.New(n)
+		r := 0
+		for i, e := range ends {
+			if subset&(1<<uint(i)) != 0 && ds.Find(e[0]) != ds.Find(e[1]) {
+				ds.Union(e[0], e[1])
+				r++
+			}
+		}
+		return r
+	}
+	fullRank := rank(1<<uint(m) - 1)
+	//counts[a][b] is the number of subsets A with r(E) - r(A) = a and |A| - r(A) = b.
+	counts := make([][]int64, fullRank+1)
+	for a := range counts {
+		counts[a] = make([]int64, m-fullRank+1)
+	}
+	reliability = make([]int64, m+1)
+	for subset := 0; subset < 1<<uint(m); subset++ {
+		r := rank(subset)
+		size := 0
+		for i := 0; i < m; i++ {
+			if subset&(1<<uint(i)) != 0 {
+				size++
+			}
+		}
+		counts[fullRank-r][size-r]++
+		if size == r {
+			forests++
+		}
+		if r == fullRank {
+			//Add p^size (1 - p)^(m - size).
+			binomial := int64(1)
+			for k := 0; k <= m-size; k++ {
+				if k%2 == 0 {
+					reliability[size+k] += binomial
+				} else {
+					reliability[size+k] -= binomial
+				}
+				binomial = binomial * int64(m-size-k) / int64(k+1)
+			}
+		}
+	}
+	//Expand (x - 1)^a (y - 1)^b.
+	binomial := func(n, k int) int64 {
+		b := int64(1)
+		for i := 0; i < k; i++ {
+			b = b * int64(n-i) / int64(i+1)
+		}
+		return b
+	}
+	tutte = make([][]int64, fullRank+1)
+	for i := range tutte {
+		tutte[i] = make([]int64, m-fullRank+1)
+	}
+	for a := range counts {
+		for b := range counts[a] {
+			for i := 0; i <= a; i++ {
+				for j := 0; j <= b; j++ {
+					sign := int64(1)
+					if (a-i+b-j)%2 == 1 {
+						sign = -1
+					}
+					tutte[i][j] += sign * counts[a][b] * binomial(a, i) * binomial(b, j)
+				}
+			}
+		}
+	}
+	return tutte, forests, reliability
+}
+
+//equalBig checks if the big integers in a are equal to the integers in b.
+func equalBig(a []*big.Int, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Cmp(big.NewInt(b[i])) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTuttePolynomial(t *testing.T) {
+	//T(K_4) = x^3 + 3x^2 + 2x + 4xy + 2y + 3y^2 + y^3.
+	k4 := [][]int64{{0, 2, 3, 1}, {2, 4, 0, 0}, {3, 0, 0, 0}, {1, 0, 0, 0}}
+	tutte := graph.TuttePolynomial(graph.CompleteGraph(4))
+	for i := range k4 {
+		if !equalBig(tutte.Coefficients[i], k4[i]) {
+			t.Fatalf("Wrong Tutte polynomial for K_4. Found: %v Expected: %v", tutte.Coefficients, k4)
+		}
+	}
+
+	for seed := int64(0); seed < 40; seed++ {
+		g := graph.RandomGraph(int(2+seed%7), float64(seed%5+3)/10, seed)
+		if g.M() > 16 {
+			continue
+		}
+		ends := make([][2]int, 0, g.M())
+		for v := 0; v < g.N(); v++ {
+			for _, u := range g.Neighbours(v) {
+				if u < v {
+					ends = append(ends, [2]int{u, v})
+				}
+			}
+		}
+		expected, forests, reliability := subsetExpansion(g.N(), ends)
+		tutte := graph.TuttePolynomial(g)
+		if len(tutte.Coefficients) != len(expected) {
+			t.Fatalf("Wrong Tutte polynomial for %v. Found: %v Expected: %v", graph.Graph6Encode(g), tutte.Coefficients, expected)
+		}
+		for i := range expected {
+			if !equalBig(tutte.Coefficients[i], expected[i]) {
+				t.Fatalf("Wrong Tutte polynomial for %v. Found: %v Expected: %v", graph.Graph6Encode(g), tutte.Coefficients, expected)
+			}
+		}
+		if tutte.NumberOfSpanningForests().Int64() != forests {
+			t.Errorf("Wrong number of spanning forests for %v. Found: %v Expected: %v", graph.Graph6Encode(g), tutte.NumberOfSpanningForests(), forests)
+		}
+		if !equalBig(tutte.ReliabilityPolynomial(), reliability) {
+			t.Errorf("Wrong reliability polynomial for %v. Found: %v Expected: %v", graph.Graph6Encode(g), tutte.ReliabilityPolynomial(), reliability)
+		}
+
+		cp := graph.ChromaticPolynomial(g)
+		found := tutte.ChromaticPolynomial()
+		for i := range cp {
+			if cp[i].Cmp(found[i]) != 0 {
+				t.Fatalf("Wrong chromatic polynomial for %v. Found: %v Expected: %v", graph.Graph6Encode(g), found, cp)
+			}
+		}
+		//The number of acyclic orientations is (-1)^n P(-1).
+		acyclic := evaluate(cp, -1)
+		if g.N()%2 == 1 {
+			acyclic.Neg(acyclic)
+		}
+		if tutte.NumberOfAcyclicOrientations().Cmp(acyclic) != 0 {
+			t.Errorf("Wrong number of acyclic orientations for %v. Found: %v Expected: %v", graph.Graph6Encode(g), tutte.NumberOfAcyclicOrientations(), acyclic)
+		}
+	}
+
+	//The number of spanning trees of K_n is n^(n - 2) and the Petersen graph has 2000 spanning trees.
+	if trees := graph.TuttePolynomial(graph.CompleteGraph(7)).NumberOfSpanningTrees(); trees.Int64() != 16807 {
+		t.Errorf("Wrong number of spanning trees for K_7. Found: %v Expected: 16807", trees)
+	}
+	if trees := graph.TuttePolynomial(graph.GeneralisedPetersenGraph(5, 2)).NumberOfSpanningTrees(); trees.Int64() != 2000 {
+		t.Errorf("Wrong number of spanning trees for the Petersen graph. Found: %v Expected: 2000", trees)
+	}
+	if trees := graph.TuttePolynomial(graph.NewDense(2, nil)).NumberOfSpanningTrees(); trees.Sign() != 0 {
+		t.Errorf("Found spanning trees of a disconnected graph")
+	}
+}
+
+func TestTuttePolynomialMultigraph(t *testing.T) {
+	//The Tutte polynomial of the planar dual is T(y, x).
+	for _, g := range []graph.Graph{graph.HypercubeGraph(3), graph.GeneralisedPetersenGraph(6, 1), graph.Path(4), graph.FriendshipGraph(3)} {
+		_, rotation, _ := graph.PlanarEmbedding(g)
+		dual := graph.PlanarDual(rotation)
+		tutte := graph.TuttePolynomial(g)
+		dualTutte := graph.TuttePolynomialMultigraph(dual)
+		if len(tutte.Coefficients) != len(dualTutte.Coefficients[0]) || len(tutte.Coefficients[0]) != len(dualTutte.Coefficients) {
+			t.Fatalf("The Tutte polynomial of the dual has the wrong shape")
+		}
+		for i := range tutte.Coefficients {
+			for j := range tutte.Coefficients[i] {
+				if tutte.Coefficients[i][j].Cmp(dualTutte.Coefficients[j][i]) != 0 {
+					t.Fatalf("The Tutte polynomial of the dual of %v is not T(y, x)", graph.Graph6Encode(g))
+				}
+			}
+		}
+	}
+
+	//The dodecahedron and the icosahedron are dual.
+	dodecahedron, _ := graph.NamedGraph("Dodecahedron")
+	icosahedron, _ := graph.NamedGraph("Icosahedron")
+	tutte := graph.TuttePolynomial(dodecahedron)
+	dualTutte := graph.TuttePolynomial(icosahedron)
+	for i := range tutte.Coefficients {
+		for j := range tutte.Coefficients[i] {
+			if tutte.Coefficients[i][j].Cmp(dualTutte.Coefficients[j][i]) != 0 {
+				t.Fatalf("The Tutte polynomial of the icosahedron is not T(y, x) for the dodecahedron")
+			}
+		}
+	}
+
+	//The flow polynomial of a plane graph is P(G*, x)/x and the dual of the cube is the octahedron.
+	flow := graph.TuttePolynomial(graph.HypercubeGraph(3)).FlowPolynomial()
+	cp := graph.ChromaticPolynomial(graph.CompletePartiteGraph(2, 2, 2))
+	for i := range flow {
+		if flow[i].Cmp(cp[i+1]) != 0 {
+			t.Fatalf("Wrong flow polynomial of the cube. Found: %v Expected: %v", flow, cp[1:])
+		}
+	}
+
+	//Two vertices joined by three edges with a loop at each vertex has Tutte polynomial y^2(x + y + y^2).
+	g := graph.NewMultigraph(2, [][2]int{{0, 1}, {1, 0}, {0, 1}, {0, 0}, {1, 1}})
+	tutte = graph.TuttePolynomialMultigraph(g)
+	if !equalBig(tutte.Coefficients[0], []int64{0, 0, 0, 1, 1}) || !equalBig(tutte.Coefficients[1], []int64{0, 0, 1, 0, 0}) {
+		t.Errorf("Wrong Tutte polynomial. Found: %v Expected: [[0 0 0 1 1] [0 0 1 0 0]]", tutte.Coefficients)
+	}
+	if tutte.NumberOfSpanningTrees().Int64() != 3 || tutte.NumberOfAcyclicOrientations().Int64() != 0 {
+		t.Errorf("Wrong specialisations of the Tutte polynomial")
+	}
+}
@@ -0,0 +1,355 @@
+//Package lp solves linear programs exactly using rational arithmetic.
+package lp
+
+import (
+	"math/big"
+)
+
+//Status is the outcome of solving a linear program.
+type Status int
+
+const (
+	//Optimal means that an optimal solution was found.
+	Optimal Status = iota
+	//Infeasible means that no point satisfies the constraints.
+	Infeasible
+	//Unbounded means that the objective function is unbounded on the points which satisfy the constraints.
+	Unbounded
+)
+
+func (s Status) String() string {
+	switch s {
+	case Optimal:
+		return "Optimal"
+	case Infeasible:
+		return "Infeasible"
+	case Unbounded:
+		return "Unbounded"
+	}
+	return "Unknown"
+}
+
+//Solution is the solution to a linear program. Value, X and Y are nil unless the status is Optimal.
+type Solution struct {
+	Status Status
+	Value  *big.Rat   //The optimal value of the objective function.
+	X      []*big.Rat //An optimal point.
+	Y      []*big.Rat //An optimal solution to the dual linear program. Y[i] is the shadow price of the ith constraint.
+}
+
+//Maximise solves the linear program which maximises c·x subject to Ax ≤ b and x ≥ 0. A nil entry of A is treated as 0.
+//The dual linear program minimises b·y subject to Aᵀy ≥ c and y ≥ 0 and the solution to this is returned in Y. See Problem for the method.
+func Maximise(c []*big.Rat, A [][]*big.Rat, b []*big.Rat) *Solution {
+	return solve(NewMaximisation(b), c, A)
+}
+
+//Minimise solves the linear program which minimises c·x subject to Ax ≥ b and x ≥ 0. A nil entry of A is treated as 0.
+//The dual linear program maximises b·y subject to Aᵀy ≤ c and y ≥ 0 and the solution to this is returned in Y. See Problem for the method.
+func Minimise(c []*big.Rat, A [][]*big.Rat, b []*big.Rat) *Solution {
+	return solve(NewMinimisation(b), c, A)
+}
+
+//solve adds the variables with the costs c and the columns of A to p and solves it.
+func solve(p *Problem, c []*big.Rat, A [][]*big.Rat) *Solution {
+	if len(A) != len(p.b) {
+		panic("A and b have a different number of rows")
+	}
+	for _, row := range A {
+		if len(row) != len(c) {
+			panic("A row of A and c have different lengths")
+		}
+	}
+	column := make([]*big.Rat, len(A))
+	for j := range c {
+		for i := range A {
+			column[i] = A[i][j]
+		}
+		p.AddVariable(c[j], column)
+	}
+	return p.Solve()
+}
+
+//Problem is a linear program where the variables are added one at a time. Variables can also be added after the problem is solved and solving the problem again starts from the previous optimal solution. This is much faster than solving the new problem from the start and is suitable for column generation.
+//The linear program is solved using the two phase simplex method with Bland's rule to avoid cycling. All the arithmetic is exact so this is only suitable for small linear programs.
+type Problem struct {
+	minimise bool
+	b        []*big.Rat //b is negated for a minimisation problem.
+	c        []*big.Rat //c is negated for a minimisation problem.
+	columns  [][]*big.Rat
+	t        *tableau //t is the tableau after the last call to Solve if the solution was optimal and nil otherwise.
+}
+
+//NewMaximisation returns a problem which maximises c·x subject to Ax ≤ b and x ≥ 0 where the variables are added with AddVariable.
+func NewMaximisation(b []*big.Rat) *Problem {
+	p := &Problem{b: make([]*big.Rat, len(b))}
+	for i := range b {
+		p.b[i] = new(big.Rat).Set(b[i])
+	}
+	return p
+}
+
+//NewMinimisation returns a problem which minimises c·x subject to Ax ≥ b and x ≥ 0 where the variables are added with AddVariable.
+func NewMinimisation(b []*big.Rat) *Problem {
+	p := &Problem{minimise: true, b: make([]*big.Rat, len(b))}
+	for i := range b {
+		p.b[i] = new(big.Rat).Neg(b[i])
+	}
+	return p
+}
+
+//AddVariable adds a variable with the coefficient c in the objective function and the coefficients in column in the constraints. A nil entry of column is treated as 0.
+func (p *Problem) AddVariable(c *big.Rat, column []*big.Rat) {
+	if len(column) != len(p.b) {
+		panic("column and b have different lengths")
+	}
+	col := make([]*big.Rat, len(column))
+	for i, v := range column {
+		col[i] = new(big.Rat)
+		if v != nil {
+			col[i].Set(v)
+			if p.minimise {
+				col[i].Neg(col[i])
+			}
+		}
+	}
+	cost := new(big.Rat).Set(c)
+	if p.minimise {
+		cost.Neg(cost)
+	}
+	p.c = append(p.c, cost)
+	p.columns = append(p.columns, col)
+
+	if t := p.t; t != nil {
+		//The new column of the tableau is B⁻¹a which is a combination of the columns of the slack variables and its entry in the objective row is y·a - c.
+		entry := new(big.Rat).Neg(cost)
+		for i, v := range col {
+			if v.Sign() != 0 {
+				entry.Add(entry, t.tmp.Mul(v, t.obj[i]))
+			}
+		}
+		t.obj = append(t.obj, entry)
+		for r := range t.rows {
+			entry := new(big.Rat)
+			for i, v := range col {
+				if v.Sign() != 0 {
+					entry.Add(entry, t.tmp.Mul(v, t.rows[r][i]))
+				}
+			}
+			t.rows[r] = append(t.rows[r], entry)
+		}
+	}
+}
+
+//Solve solves the linear program. If the previous call to Solve found an optimal solution, this starts from that solution.
+func (p *Problem) Solve() *Solution {
+	m := len(p.b)
+	if p.t == nil {
+		p.t = newTableau(p.b, p.columns)
+		if !p.t.phaseOne() {
+			p.t = nil
+			return &Solution{Status: Infeasible}
+		}
+		p.t.setObjective(p.c)
+	}
+	t := p.t
+	if !t.simplex(false) {
+		p.t = nil
+		return &Solution{Status: Unbounded}
+	}
+
+	solution := &Solution{Status: Optimal, Value: new(big.Rat).Set(t.value), X: make([]*big.Rat, len(p.columns)), Y: make([]*big.Rat, m)}
+	if p.minimise {
+		solution.Value.Neg(solution.Value)
+	}
+	for j := range solution.X {
+		solution.X[j] = new(big.Rat)
+	}
+	first := m + t.artificial
+	for i, j := range t.basis {
+		if j >= first {
+			solution.X[j-first].Set(t.rhs[i])
+		}
+	}
+	for i := range solution.Y {
+		solution.Y[i] = new(big.Rat).Set(t.obj[i])
+	}
+	return solution
+}
+
+//tableau is a simplex tableau. The columns are the m slack variables, an artificial variable for each row with a negative right hand side and then the variables of the problem.
+//The objective row obj represents z - c·x = 0 so value is the current value of the objective function and a negative entry of obj is a column which can improve the objective function.
+type tableau struct {
+	rows       [][]*big.Rat
+	rhs        []*big.Rat
+	obj        []*big.Rat
+	value      *big.Rat
+	basis      []int
+	artificial int
+	tmp        *big.Rat
+}
+
+//newTableau returns the tableau for Ax + s = b where the columns of A are given by columns. Each row with a negative right hand side is negated and an artificial variable is added to it.
+func newTableau(b []*big.Rat, columns [][]*big.Rat) *tableau {
+	m := len(b)
+	t := &tableau{rows: make([][]*big.Rat, m), rhs: make([]*big.Rat, m), value: new(big.Rat), basis: make([]int, m), tmp: new(big.Rat)}
+	for _, v := range b {
+		if v.Sign() < 0 {
+			t.artificial++
+		}
+	}
+	width := m + t.artificial + len(columns)
+	next := m
+	for i := range t.rows {
+		row := make([]*big.Rat, width)
+		for j := range row {
+			row[j] = new(big.Rat)
+		}
+		row[i].SetInt64(1)
+		for j, column := range columns {
+			row[m+t.artificial+j].Set(column[i])
+		}
+		t.rhs[i] = new(big.Rat).Set(b[i])
+		t.basis[i] = i
+		if b[i].Sign() < 0 {
+			for _, v := range row {
+				v.Neg(v)
+			}
+			t.rhs[i].Neg(t.rhs[i])
+			row[next].SetInt64(1)
+			t.basis[i] = next
+			next++
+		}
+		t.rows[i] = row
+	}
+	t.obj = make([]*big.Rat, width)
+	for j := range t.obj {
+		t.obj[j] = new(big.Rat)
+	}
+	return t
+}
+
+//phaseOne finds a basic feasible solution by maximising minus the sum of the artificial variables and returns false if there isn't one.
+func (t *tableau) phaseOne() bool {
+	m := len(t.rows)
+	for j := m; j < m+t.artificial; j++ {
+		t.obj[j].SetInt64(1)
+	}
+	t.price()
+	t.simplex(true)
+	if t.value.Sign() < 0 {
+		return false
+	}
+	//Remove the artificial variables from the basis where possible. Any which remain are in a redundant row.
+	for i, j := range t.basis {
+		if j < m || j >= m+t.artificial {
+			continue
+		}
+		for k, v := range t.rows[i] {
+			if (k < m || k >= m+t.artificial) && v.Sign() != 0 {
+				t.pivot(i, k)
+				break
+			}
+		}
+	}
+	return true
+}
+
+//setObjective sets the objective function to c·x where x are the variables of the problem.
+func (t *tableau) setObjective(c []*big.Rat) {
+	first := len(t.rows) + t.artificial
+	for j := range t.obj {
+		t.obj[j].SetInt64(0)
+	}
+	t.value.SetInt64(0)
+	for j := range c {
+		t.obj[first+j].Neg(c[j])
+	}
+	t.price()
+}
+
+//price subtracts multiples of the rows from the objective row so that the entries for the basic variables are 0.
+func (t *tableau) price() {
+	f := new(big.Rat)
+	for i, j := range t.basis {
+		if t.obj[j].Sign() != 0 {
+			f.Set(t.obj[j])
+			t.subtract(t.obj, t.value, i, f)
+		}
+	}
+}
+
+//subtract subtracts f times the row r from row and f times the right hand side of r from value.
+func (t *tableau) subtract(row []*big.Rat, value *big.Rat, r int, f *big.Rat) {
+	for k, v := range t.rows[r] {
+		if v.Sign() != 0 {
+			row[k].Sub(row[k], t.tmp.Mul(f, v))
+		}
+	}
+	value.Sub(value, t.tmp.Mul(f, t.rhs[r]))
+}
+
+//pivot makes the variable j basic in row r.
+func (t *tableau) pivot(r, j int) {
+	p := new(big.Rat).Inv(t.rows[r][j])
+	for _, v := range t.rows[r] {
+		if v.Sign() != 0 {
+			v.Mul(v, p)
+		}
+	}
+	t.rhs[r].Mul(t.rhs[r], p)
+	f := new(big.Rat)
+	for i, row := range t.rows {
+		if i != r && row[j].Sign() != 0 {
+			f.Set(row[j])
+			t.subtract(row, t.rhs[i], r, f)
+		}
+	}
+	if t.obj[j].Sign() != 0 {
+		f.Set(t.obj[j])
+		t.subtract(t.obj, t.value, r, f)
+	}
+	t.basis[r] = j
+}
+
+//simplex pivots until the objective function can't be improved and returns true or until the objective function is unbounded and returns false. The artificial variables are only used if artificial is true.
+//The entering variable is the first column which improves the objective function and the leaving variable is the one with the smallest index among those which minimise the ratio. This is Bland's rule and it prevents cycling.
+func (t *tableau) simplex(artificial bool) bool {
+	m := len(t.rows)
+	ratio := new(big.Rat)
+	best := new(big.Rat)
+	for {
+		j := -1
+		for k, v := range t.obj {
+			if !artificial && k >= m && k < m+t.artificial {
+				continue
+			}
+			if v.Sign() < 0 {
+				j = k
+				break
+			}
+		}
+		if j == -1 {
+			return true
+		}
+		r := -1
+		for i, row := range t.rows {
+			if row[j].Sign() <= 0 {
+				continue
+			}
+			ratio.Quo(t.rhs[i], row[j])
+			if r == -1 {
+				r = i
+				best.Set(ratio)
+				continue
+			}
+			if cmp := ratio.Cmp(best); cmp < 0 || (cmp == 0 && t.basis[i] < t.basis[r]) {
+				r = i
+				best.Set(ratio)
+			}
+		}
+		if r == -1 {
+			return false
+		}
+		t.pivot(r, j)
+	}
+}
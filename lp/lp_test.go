@@ -0,0 +1,173 @@
+package lp
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+//rats converts the integers in a to rationals.
+func rats(a ...int64) []*big.Rat {
+	r := make([]*big.Rat, len(a))
+	for i, v := range a {
+		r[i] = big.NewRat(v, 1)
+	}
+	return r
+}
+
+//dot returns a·b.
+func dot(a, b []*big.Rat) *big.Rat {
+	sum := new(big.Rat)
+	for i := range a {
+		if a[i] != nil {
+			sum.Add(sum, new(big.Rat).Mul(a[i], b[i]))
+		}
+	}
+	return sum
+}
+
+func TestMaximise(t *testing.T) {
+	//Maximise 3x + 5y subject to x ≤ 4, 2y ≤ 12 and 3x + 2y ≤ 18.
+	solution := Maximise(rats(3, 5), [][]*big.Rat{rats(1, 0), rats(0, 2), rats(3, 2)}, rats(4, 12, 18))
+	if solution.Status != Optimal || solution.Value.Cmp(big.NewRat(36, 1)) != 0 {
+		t.Fatalf("Wrong solution. Found: %v %v Expected: Optimal 36", solution.Status, solution.Value)
+	}
+	expectedY := []*big.Rat{big.NewRat(0, 1), big.NewRat(3, 2), big.NewRat(1, 1)}
+	for i := range expectedY {
+		if solution.Y[i].Cmp(expectedY[i]) != 0 {
+			t.Errorf("Wrong dual solution. Found: %v Expected: %v", solution.Y, expectedY)
+		}
+	}
+
+	if solution := Maximise(rats(1), [][]*big.Rat{rats(1)}, rats(-1)); solution.Status != Infeasible {
+		t.Errorf("Wrong status. Found: %v Expected: Infeasible", solution.Status)
+	}
+	if solution := Maximise(rats(1, 1), [][]*big.Rat{rats(1, -1)}, rats(1)); solution.Status != Unbounded {
+		t.Errorf("Wrong status. Found: %v Expected: Unbounded", solution.Status)
+	}
+
+	//Beale's example cycles with the largest coefficient rule.
+	c := []*big.Rat{big.NewRat(3, 4), big.NewRat(-20, 1), big.NewRat(1, 2), big.NewRat(-6, 1)}
+	A := [][]*big.Rat{
+		{big.NewRat(1, 4), big.NewRat(-8, 1), big.NewRat(-1, 1), big.NewRat(9, 1)},
+		{big.NewRat(1, 2), big.NewRat(-12, 1), big.NewRat(-1, 2), big.NewRat(3, 1)},
+		{nil, nil, big.NewRat(1, 1), nil},
+	}
+	if solution := Maximise(c, A, rats(0, 0, 1)); solution.Status != Optimal || solution.Value.Cmp(big.NewRat(5, 4)) != 0 {
+		t.Errorf("Wrong solution to Beale's example. Found: %v %v Expected: Optimal 5/4", solution.Status, solution.Value)
+	}
+}
+
+func TestDuality(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for k := 0; k < 300; k++ {
+		n := 1 + r.Intn(6)
+		m := 1 + r.Intn(6)
+		c := make([]*big.Rat, n)
+		for j := range c {
+			c[j] = big.NewRat(int64(r.Intn(11)-5), int64(1+r.Intn(3)))
+		}
+		A := make([][]*big.Rat, m)
+		b := make([]*big.Rat, m)
+		for i := range A {
+			A[i] = make([]*big.Rat, n)
+			for j := range A[i] {
+				if r.Intn(4) > 0 {
+					A[i][j] = big.NewRat(int64(r.Intn(11)-5), 1)
+				}
+			}
+			b[i] = big.NewRat(int64(r.Intn(16)-5), 1)
+		}
+		//The dual minimises b·y subject to Aᵀy ≥ c and y ≥ 0.
+		transpose := make([][]*big.Rat, n)
+		for j := range transpose {
+			transpose[j] = make([]*big.Rat, m)
+			for i := range A {
+				transpose[j][i] = A[i][j]
+			}
+		}
+		primal := Maximise(c, A, b)
+		dual := Minimise(b, transpose, c)
+
+		switch primal.Status {
+		case Optimal:
+			for i := range A {
+				if dot(A[i], primal.X).Cmp(b[i]) > 0 {
+					t.Fatalf("The solution %v doesn't satisfy the constraints", primal.X)
+				}
+			}
+			for j := range primal.X {
+				if primal.X[j].Sign() < 0 || dot(transpose[j], primal.Y).Cmp(c[j]) < 0 {
+					t.Fatalf("The solution %v or the dual solution %v isn't feasible", primal.X, primal.Y)
+				}
+			}
+			for i := range primal.Y {
+				if primal.Y[i].Sign() < 0 {
+					t.Fatalf("The dual solution %v isn't feasible", primal.Y)
+				}
+			}
+			if dot(c, primal.X).Cmp(primal.Value) != 0 || dot(b, primal.Y).Cmp(primal.Value) != 0 {
+				t.Fatalf("The objective values don't match the value %v", primal.Value)
+			}
+			if dual.Status != Optimal || dual.Value.Cmp(primal.Value) != 0 {
+				t.Fatalf("The dual has the wrong solution. Found: %v %v Expected: Optimal %v", dual.Status, dual.Value, primal.Value)
+			}
+		case Unbounded:
+			if dual.Status != Infeasible {
+				t.Fatalf("The primal is unbounded but the dual is %v", dual.Status)
+			}
+		case Infeasible:
+			if dual.Status == Optimal {
+				t.Fatalf("The primal is infeasible but the dual is optimal")
+			}
+		}
+	}
+}
+
+func TestProblem(t *testing.T) {
+	//Adding the variables one at a time and solving after each one gives the same value as solving at the end.
+	r := rand.New(rand.NewSource(1))
+	for k := 0; k < 100; k++ {
+		n := 1 + r.Intn(8)
+		m := 1 + r.Intn(6)
+		b := make([]*big.Rat, m)
+		for i := range b {
+			b[i] = big.NewRat(int64(r.Intn(16)-5), 1)
+		}
+		maximisation := NewMaximisation(b)
+		minimisation := NewMinimisation(b)
+		c := make([]*big.Rat, n)
+		A := make([][]*big.Rat, m)
+		for i := range A {
+			A[i] = make([]*big.Rat, n)
+		}
+		for j := range c {
+			c[j] = big.NewRat(int64(r.Intn(11)-5), 1)
+			column := make([]*big.Rat, m)
+			for i := range column {
+				column[i] = big.NewRat(int64(r.Intn(11)-5), 1)
+				A[i][j] = column[i]
+			}
+			maximisation.AddVariable(c[j], column)
+			minimisation.AddVariable(c[j], column)
+			for _, test := range []struct {
+				p        *Problem
+				expected *Solution
+			}{{maximisation, Maximise(c[:j+1], prefix(A, j+1), b)}, {minimisation, Minimise(c[:j+1], prefix(A, j+1), b)}} {
+				found := test.p.Solve()
+				if found.Status != test.expected.Status || (found.Status == Optimal && found.Value.Cmp(test.expected.Value) != 0) {
+					t.Fatalf("Wrong solution after adding a variable. Found: %v %v Expected: %v %v", found.Status, found.Value, test.expected.Status, test.expected.Value)
+				}
+			}
+		}
+	}
+}
+
+//prefix returns the first k columns of A.
+func prefix(A [][]*big.Rat, k int) [][]*big.Rat {
+	B := make([][]*big.Rat, len(A))
+	for i := range A {
+		B[i] = A[i][:k]
+	}
+	return B
+}